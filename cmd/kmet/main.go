@@ -2,33 +2,75 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"log"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/HaPhanBaoMinh/kmet/help"
 	"github.com/HaPhanBaoMinh/kmet/internal/app"
+	"github.com/HaPhanBaoMinh/kmet/internal/config"
 	"github.com/HaPhanBaoMinh/kmet/internal/domain"
 	kk "github.com/HaPhanBaoMinh/kmet/internal/infrastructure/k8s"
 	"github.com/HaPhanBaoMinh/kmet/internal/infrastructure/mock"
+	"github.com/HaPhanBaoMinh/kmet/internal/logs"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/styles"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		configCmd(os.Args[2:])
+		return
+	}
+
 	var useMock bool
 	var kubeconfig, contextName string
+	var opts app.Options
+	var logTee string
+	var errBudget int
+	var labelSel, fieldSel string
 	flag.BoolVar(&useMock, "mock", false, "use mock repo")
 	flag.StringVar(&kubeconfig, "kubeconfig", filepath.Join(help.HomeDir(), ".kube", "config"), "path to kubeconfig")
 	flag.StringVar(&contextName, "context", "", "kube context")
+	flag.IntVar(&opts.LogMaxLines, "log-lines", 10000, "max log lines kept in the logs pane")
+	flag.IntVar(&opts.LogMaxBytes, "log-bytes", 16<<20, "max bytes of log text kept in the logs pane")
+	flag.StringVar(&logTee, "log-tee", "", "also write every opened log stream to rotating per-source files in this directory")
+	flag.IntVar(&errBudget, "err-rate", 0, "tail logs of up to this many pods in view to show an ERR/min column (0 disables)")
+	flag.StringVar(&labelSel, "l", "", "label selector for pods (shorthand for -selector)")
+	flag.StringVar(&labelSel, "selector", "", "label selector for pods, e.g. app=api,tier!=cache")
+	flag.StringVar(&fieldSel, "field-selector", "", "field selector for pods, e.g. status.phase!=Running")
+	flag.StringVar(&opts.Theme, "theme", "", "color theme: "+strings.Join(styles.Themes(), ", ")+" or a palette from the config")
 	flag.Parse()
 
+	sel, err := app.ParseSelector(labelSel, fieldSel)
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts.Selector = sel
+
+	opts.ConfigPath = config.Path()
+	if opts.Config, err = config.Load(opts.ConfigPath); err != nil {
+		log.Fatal(err)
+	}
+	if err := app.CheckConfig(opts.Config); err != nil {
+		log.Fatalf("%s: %v", opts.ConfigPath, err)
+	}
+	if err := app.CheckTheme(opts.Config, opts.Theme); err != nil {
+		log.Fatalf("-theme: %v", err)
+	}
+
 	var repoM domain.MetricsRepo // actually domain.MetricsRepo, but shortcut in this file
 	var repoL domain.LogsRepo
 
 	if useMock {
 		repo := mock.New()
 		repoM, repoL = repo, repo
+		opts.Context = "mock"
 	} else {
+		opts.Context = kk.CurrentContext(kubeconfig, contextName)
 		repo, err := kk.New(kubeconfig, contextName)
 		if err != nil {
 			log.Fatal(err)
@@ -36,8 +78,50 @@ func main() {
 		repoM, repoL = repo, repo
 	}
 
-	m := app.New(repoM, repoL)
-	if err := tea.NewProgram(m, tea.WithAltScreen()).Start(); err != nil {
+	// the sampler reads the repo directly so its streams aren't teed to disk
+	if errBudget > 0 {
+		opts.ErrRate = logs.NewSampler(repoL, errBudget)
+	}
+
+	if logTee != "" {
+		tee, err := logs.NewTee(repoL, logTee)
+		if err != nil {
+			log.Fatal(err)
+		}
+		repoL = tee
+	}
+
+	m := app.New(repoM, repoL, opts)
+	if err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion()).Start(); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// configCmd handles "kmet config init" and "kmet config path".
+func configCmd(args []string) {
+	fs := flag.NewFlagSet("config", flag.ExitOnError)
+	force := fs.Bool("force", false, "overwrite an existing config file")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: kmet config init [-force] | kmet config path")
+		fs.PrintDefaults()
+	}
+	if len(args) == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	fs.Parse(args[1:])
+
+	path := config.Path()
+	switch args[0] {
+	case "init":
+		if err := config.Init(path, *force); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("wrote", path)
+	case "path":
+		fmt.Println(path)
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+}
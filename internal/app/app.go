@@ -5,22 +5,34 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"regexp"
 	"strings"
 	"time"
 
-	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/HaPhanBaoMinh/kmet/internal/config"
 	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+	"github.com/HaPhanBaoMinh/kmet/internal/filter"
+	"github.com/HaPhanBaoMinh/kmet/internal/logs"
 	"github.com/HaPhanBaoMinh/kmet/internal/ui/styles"
 	"github.com/HaPhanBaoMinh/kmet/internal/ui/widgets"
 )
 
 type View int
-type logLineMsg domain.LogLine
-type streamDone struct{}
+
+// log stream messages carry their channel so lines from a stream that was
+// replaced in the meantime can be ignored.
+type logStreamMsg struct{ ch <-chan domain.LogLine }
+type logBatchMsg struct {
+	ch    <-chan domain.LogLine
+	lines []domain.LogLine
+}
+type streamDone struct{ ch <-chan domain.LogLine }
 
 const (
 	ViewPods View = iota
@@ -34,41 +46,111 @@ type Model struct {
 	repoM domain.MetricsRepo
 	repoL domain.LogsRepo
 
-	// Namespace picker
-	nsPickerOpen bool
-	nsTable      table.Model
-	autoCursor   bool
+	picker     picker // namespace / node log service chooser overlay
+	autoCursor bool
 
 	view     View
 	ns       string
 	nsList   []string
-	selector string
-	sortBy   string // "cpu"|"mem"
-
-	table table.Model
+	selector domain.Selector
+	podSort  sortSpec
+	nodeSort sortSpec
+	podCols  []config.Column // see columns.go
+	nodeCols []config.Column
+	chooser  chooser   // column chooser overlay
+	detail   detail    // full-screen page of one pod or node, see detail.go
+	heat     heatmap   // full-screen node heatmap, see heatmap.go
+	tree     treemap   // full-screen namespace treemap, see treemap.go
+	dash     dashboard // full-screen dashboard of several panes, see dashboard.go
+
+	table widgets.Table
 
 	// panes
 	infoOpen   bool
 	logsOpen   bool
 	logsVP     viewport.Model
 	logsCancel context.CancelFunc
-
-	// cache
-	pods  []domain.PodMetric
-	nodes []domain.NodeMetric
+	tableShare float64    // of the rows left with logs open, 0 = default; see layout
+	infoGrow   int        // rows the info charts gained by dragging, may be < 0
+	drag       paneBorder // the pane border being dragged, see mouse.go
+
+	// cache: all* is the last fetch, pods/nodes what the find filter lets through
+	allPods  []domain.PodMetric
+	allNodes []domain.NodeMetric
+	pods     []domain.PodMetric
+	nodes    []domain.NodeMetric
+	nodeRows []nodeRef                     // what each Nodes table row shows, see tree.go
+	expanded map[string]bool               // nodes opened into their pods
+	nodePods map[string][]domain.PodMetric // pods of the open nodes, by CPU
+	find     string                        // fuzzy filter over names, kept across refreshes
+	exprText string                        // filter expression as typed, see query.go
+	podExpr  *filter.Expr
+	nodeExpr *filter.Expr // nil when the expression uses pod-only fields
 
 	width, height int
 	ticker        *time.Ticker
 	err           error
 
-	logCh  <-chan domain.LogLine
-	logBuf strings.Builder
+	logCh         <-chan domain.LogLine
+	logTarget     domain.LogsTarget
+	logRange      int // index into logRanges
+	logPrevious   bool
+	logRaw        bool // show lines as received instead of parsed columns
+	logRing       *logs.Ring
+	logView       []string // rendered rows that passed level/filter
+	logTop        int      // first logView row in the pane
+	logFollow     bool     // keep the newest row in view
+	logViewSeq    []uint64 // ring sequence number of each row
+	logCols       logColumns
+	logDropped    int // lines the producers dropped because we fell behind
+	logLevel      logLevel
+	logFilter     logFilter
+	logSearch     *regexp.Regexp
+	logSearchText string
+	logMatches    []int // viewport rows holding a search match
+	logMatchIdx   int
+
+	// inline prompt (search, filter)
+	prompt     textinput.Model
+	promptKind promptKind
+	promptErr  string
+	status     string // one-shot message in the footer, cleared on next key
+
+	exportRaw bool
+
+	errRate *logs.Sampler // background WARN/ERROR counter, nil when disabled
+
+	cfg        config.Config
+	cfgPath    string
+	kctx       string // kube context, picks per-context settings
+	refresh    time.Duration
+	thresholds map[string]config.Level // see settings.go
+	keys       keyMap                  // see keys.go
+	dashLayout config.Dashboard        // rows of dashboard panes
+	count      int                     // pending motion count ("5j")
+	helpMode   keyMode                 // mode the help overlay shows, 0 = closed
+}
+
+// Options tunes the model; zero values fall back to defaults.
+type Options struct {
+	LogMaxLines int // lines kept in the logs pane
+	LogMaxBytes int // bytes of log text kept in the logs pane
+
+	Selector domain.Selector // initial pod selector, see ParseSelector
+
+	Config     config.Config
+	ConfigPath string // where named filters and columns are saved
+	Context    string // kube context name, for per-context settings
+	Theme      string // overrides the config's theme
+
+	// ErrRate samples pod logs for the ERR/min column; nil disables it.
+	ErrRate *logs.Sampler
 }
 
-func New(repoM domain.MetricsRepo, repoL domain.LogsRepo) Model {
+func New(repoM domain.MetricsRepo, repoL domain.LogsRepo, opts Options) Model {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	t := table.New()
+	t := widgets.NewTable()
 	t.SetHeight(12)
 	t.SetWidth(100)
 
@@ -78,13 +160,24 @@ func New(repoM domain.MetricsRepo, repoL domain.LogsRepo) Model {
 		repoM:      repoM,
 		repoL:      repoL,
 		view:       ViewPods,
-		ns:         "default",
 		autoCursor: false,
-		sortBy:     "cpu",
+		podSort:    sortSpec{key: "cpu", desc: true},
+		nodeSort:   sortSpec{key: "cpu", desc: true},
 		table:      t,
 		logsVP:     viewport.New(10, 100),
+		prompt:     newPrompt(),
+		logRing:    logs.NewRing(config.Or(opts.LogMaxLines, 10000), config.Or(opts.LogMaxBytes, 16<<20)),
+		errRate:    opts.ErrRate,
+		selector:   opts.Selector,
+		cfg:        opts.Config,
+		cfgPath:    opts.ConfigPath,
+		kctx:       opts.Context,
 	}
-	m.ticker = time.NewTicker(2 * time.Second)
+	definePalettes(opts.Config.Themes)
+	s := opts.Config.For(opts.Context)
+	s.Theme = config.Or(opts.Theme, s.Theme)
+	m.applySettings(s)
+	m.ticker = time.NewTicker(m.refresh)
 
 	// Get list namespace
 	if repoM != nil {
@@ -97,24 +190,13 @@ func New(repoM domain.MetricsRepo, repoL domain.LogsRepo) Model {
 		m.nsList = []string{"default"}
 	}
 
-	// init ns picker table
-	m.nsTable = table.New()
-	m.nsTable.SetColumns([]table.Column{{Title: "Namespaces", Width: 32}})
-	var nsRows []table.Row
-	for _, ns := range m.nsList {
-		nsRows = append(nsRows, table.Row{ns})
-	}
-	m.nsTable.SetRows(nsRows)
-	m.nsTable.SetHeight(10)
-	m.nsTable.SetWidth(36)
-
 	return m
 }
 
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		m.fetch(),
-		tea.Tick(2*time.Second, func(time.Time) tea.Msg { return tickMsg{} }),
+		tea.Tick(m.refresh, func(time.Time) tea.Msg { return tickMsg{} }),
 	)
 }
 
@@ -123,14 +205,34 @@ type tickMsg struct{}
 type podsMsg []domain.PodMetric
 type nodesMsg []domain.NodeMetric
 type errMsg struct{ error }
+type nodeLogServicesMsg struct {
+	node  string
+	items []string
+}
+
+// maxLogBatch bounds how many lines are folded into one update, so a burst
+// costs one render instead of one per line.
+const maxLogBatch = 512
 
 func readNextLog(ch <-chan domain.LogLine) tea.Cmd {
 	return func() tea.Msg {
 		ln, ok := <-ch
 		if !ok {
-			return streamDone{}
+			return streamDone{ch}
+		}
+		batch := []domain.LogLine{ln}
+		for len(batch) < maxLogBatch {
+			select {
+			case ln, ok := <-ch:
+				if !ok {
+					return logBatchMsg{ch, batch}
+				}
+				batch = append(batch, ln)
+			default:
+				return logBatchMsg{ch, batch}
+			}
 		}
-		return logLineMsg(ln)
+		return logBatchMsg{ch, batch}
 	}
 }
 
@@ -142,16 +244,12 @@ func (m Model) fetch() tea.Cmd {
 			if err != nil {
 				return errMsg{err}
 			}
-			mx := p
-			sortPods(mx, m.sortBy)
 			return podsMsg(p)
 		case ViewNodes:
 			n, err := m.repoM.ListNodes(m.ctx)
 			if err != nil {
 				return errMsg{err}
 			}
-			mx := n
-			sortNodes(mx, m.sortBy)
 			return nodesMsg(n)
 		}
 		return dataMsg{}
@@ -163,42 +261,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.WindowSizeMsg:
 		m.width, m.height = msg.Width, msg.Height
-
-		// compute vertical layout using measured header/footer, not magic numbers
-		headerH := lipgloss.Height(styles.Header.Render("x"))
-		footerH := lipgloss.Height(styles.Footer.Render("x"))
-		base := m.height - headerH - footerH - 2 // top/bot padding
-
-		if base < 10 {
-			base = 10
-		}
-
-		switch {
-		case m.infoOpen && m.logsOpen:
-			m.table.SetHeight(int(float64(base) * 0.5))
-			m.logsVP.Width = m.width - 4
-			m.logsVP.Height = int(float64(base) * 0.3)
-
-		case m.logsOpen:
-			m.table.SetHeight(int(float64(base) * 0.55))
-			m.logsVP.Width = m.width - 4
-			m.logsVP.Height = base - m.table.Height()
-
-		case m.infoOpen:
-			m.table.SetHeight(int(float64(base) * 0.65))
-			m.logsVP.Width = m.width - 4
-			m.logsVP.Height = 0
-
-		default:
-			m.table.SetHeight(base)
-			m.logsVP.Width = m.width - 4
-			m.logsVP.Height = 0
-		}
-		// table target width = terminal width minus side padding/borders
-		m.table.SetWidth(m.width - 4)
-
-		// rebuild columns with new widths
-		m.rebuildTable()
+		m.layout()
+		m.renderDetail()
 
 		// forward resize to viewport for internal state updates
 		var cmd tea.Cmd
@@ -206,8 +270,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 
 	case podsMsg:
-		m.pods = msg
+		m.sortPods(msg)
+		m.allPods = msg
+		m.applyFilters()
+		m.sampleErrors()
 		m.rebuildTable()
+		m.renderDetail()
 
 		rows := len(m.pods)
 		cur := m.table.Cursor()
@@ -220,10 +288,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case nodesMsg:
-		m.nodes = msg
+		m.sortNodes(msg)
+		m.allNodes = msg
+		m.applyFilters()
 		m.rebuildTable()
+		m.renderDetail()
 
-		rows := len(m.nodes)
+		rows := len(m.nodeRows)
 		cur := m.table.Cursor()
 		if rows == 0 {
 			// nothing to select
@@ -233,17 +304,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.autoCursor = false
 		return m, nil
 
-	case logLineMsg:
-		ln := domain.LogLine(msg)
-		m.logBuf.WriteString(fmt.Sprintf("%s %-5s %s [%s]\n",
-			ln.Time.Format("15:04:05.000"), ln.Level, ln.Text, ln.Source))
-		m.logsVP.SetContent(m.logBuf.String())
-		m.logsVP.GotoBottom()
+	case logStreamMsg:
+		m.logCh = msg.ch
+		return m, readNextLog(msg.ch)
+
+	case logBatchMsg:
+		if msg.ch != m.logCh {
+			return m, nil // stale stream
+		}
+		m.appendLogs(msg.lines)
 		return m, readNextLog(m.logCh)
 
 	case streamDone:
 		return m, nil
 
+	case nodeLogServicesMsg:
+		m.picker = newPicker(pickNodeLog, "Node logs: "+msg.node, "Service", msg.items, "kubelet")
+		m.picker.node = msg.node
+		return m, nil
+
 	case dataMsg:
 		m.rebuildTable()
 		if (m.view == ViewPods && len(m.pods) > 0) ||
@@ -252,120 +331,209 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case detailMsg:
+		m.setDetail(msg)
+		return m, nil
+
+	case heatmapMsg:
+		m.setHeatmap(msg)
+		return m, nil
+
+	case treemapMsg:
+		m.setTreemap(msg)
+		return m, nil
+
+	case nodePodsMsg:
+		m.setNodePods(msg)
+		return m, nil
+
+	case dashboardMsg:
+		m.setDashboard(msg)
+		return m, nil
+
 	case tickMsg:
+		var detail, heat, tree, dash tea.Cmd
+		if m.detail.open() {
+			detail = m.fetchDetail()
+		}
+		if m.heat.on {
+			heat = m.fetchHeatmap()
+		}
+		if m.tree.on {
+			tree = m.fetchTreemap()
+		}
+		if m.dash.on {
+			dash = m.fetchDashboard()
+		}
 		return m, tea.Batch(
 			m.fetch(),
-			tea.Tick(500*time.Millisecond, func(time.Time) tea.Msg { return tickMsg{} }),
+			m.fetchExpanded(),
+			detail,
+			heat,
+			tree,
+			dash,
+			tea.Tick(m.refresh, func(time.Time) tea.Msg { return tickMsg{} }),
 		)
 
+	case tea.MouseMsg:
+		return m.updateMouse(msg)
+
 	case tea.KeyMsg:
+		m.status = ""
+		if msg.String() == "ctrl+c" {
+			m.closeLogs()
+			m.stopSampler()
+			m.cancel()
+			return m, tea.Quit
+		}
+		if m.promptKind != promptNone {
+			return m.updatePrompt(msg)
+		}
+		if m.helpMode != 0 {
+			m.helpMode = 0 // any key closes help
+			return m, nil
+		}
+		if m.takeCount(msg) {
+			return m, nil
+		}
+		if key.Matches(msg, m.keys.Help) && m.keyMode()&(modeMain|modeLogs|modeDetail|modeHeatmap|modeTreemap|modeDashboard) != 0 {
+			m.helpMode = m.keyMode()
+			m.count = 0
+			return m, nil
+		}
+		if m.detail.open() {
+			return m.updateDetail(msg)
+		}
+		if m.heat.on {
+			return m.updateHeatmap(msg)
+		}
+		if m.tree.on {
+			return m.updateTreemap(msg)
+		}
+		if m.dash.on {
+			return m.updateDashboard(msg)
+		}
 		if m.logsOpen {
-			var cmd tea.Cmd
-			m.logsVP, cmd = m.logsVP.Update(msg)
-			return m, cmd
-		}
-		if m.nsPickerOpen {
-			switch msg.String() {
-			case "enter":
-				if len(m.nsList) > 0 {
-					idx := m.nsTable.Cursor()
-					if idx < 0 {
-						idx = 0
-					}
-					if idx >= len(m.nsList) {
-						idx = len(m.nsList) - 1
-					}
-					newNS := m.nsList[idx]
-					if newNS != "" && newNS != m.ns {
-						m.ns = newNS
-						m.table.SetCursor(0)
-						m.infoOpen, m.logsOpen = false, false
-						if m.logsCancel != nil {
-							m.logsCancel()
-						}
-						m.nsPickerOpen = false
-						return m, m.fetch()
-					}
-				}
-				m.nsPickerOpen = false
-				return m, nil
-			case "up", "k", "down", "j", "pgup", "pgdn", "home", "end":
-				var cmd tea.Cmd
-				m.nsTable, cmd = m.nsTable.Update(msg)
-				return m, cmd
-			}
+			return m.updateLogsKeys(msg)
+		}
+		if m.picker.open() {
+			return m.updatePicker(msg)
+		}
+		if m.chooser.open() {
+			return m.updateChooser(msg)
+		}
+		if m.moveTable(msg) {
+			return m, nil
 		}
 
-		switch msg.String() {
-		case "ctrl+c", "q":
+		k := m.keys
+		switch {
+		case key.Matches(msg, k.Quit):
 			if m.logsCancel != nil {
 				m.logsCancel()
 			}
-			m.nsTable.Blur()
+			m.stopSampler()
 			m.cancel()
 			return m, tea.Quit
 
-		case "n":
-			m.nsPickerOpen = true
-			m.nsTable.Focus()
-			cur := 0
-			for i, v := range m.nsList {
-				if v == m.ns {
-					cur = i
-					break
-				}
-			}
-			m.nsTable.SetCursor(cur)
+		case key.Matches(msg, k.Columns):
+			m.chooser = m.newChooser(*m.viewColumns())
 			return m, nil
 
-		case "tab":
-			if m.view == ViewPods {
-				m.view = ViewNodes
-			} else {
-				m.view = ViewPods
-			}
-			m.infoOpen, m.logsOpen = false, false
-			m.autoCursor = true
-			return m, m.fetch()
+		case key.Matches(msg, k.Namespace):
+			m.picker = newPicker(pickNamespace, "Switch Namespace", "Namespaces", m.nsList, m.ns)
+			return m, nil
+
+		case key.Matches(msg, k.SwitchView):
+			return m, m.switchView()
 
-		case "i":
+		case key.Matches(msg, k.Info):
 			m.infoOpen = true
 			// trigger a synthetic resize to recalc heights
-			return m, func() tea.Msg { return tea.WindowSizeMsg{Width: m.width, Height: m.height} }
+			return m, m.relayout()
+
+		case m.view == ViewNodes && key.Matches(msg, k.Expand):
+			return m, m.toggleNode()
+		case m.view == ViewNodes && key.Matches(msg, k.NodeDetail),
+			m.view == ViewPods && key.Matches(msg, k.Detail):
+			return m, m.openDetail("")
+		case key.Matches(msg, k.Manifest):
+			return m, m.openDetail("YAML")
+		case key.Matches(msg, k.Describe):
+			return m, m.openDetail("Describe")
+
+		case key.Matches(msg, k.Heatmap):
+			return m, m.openHeatmap()
+		case key.Matches(msg, k.Treemap):
+			return m, m.openTreemap()
+		case key.Matches(msg, k.Dashboard):
+			return m, m.openDashboard()
+		case key.Matches(msg, k.Pin):
+			return m, m.pin(m.currentLogsTarget())
+
+		case key.Matches(msg, k.Logs):
+			t := m.currentLogsTarget()
+			if t.Name == "" {
+				return m, nil
+			}
+			if m.view == ViewNodes {
+				return m, m.fetchNodeLogServices(t.Name)
+			}
+			m.logPrevious = false
+			return m, m.openLogs(t)
 
-		case "esc":
-			if m.infoOpen {
-				m.infoOpen = false
+		case key.Matches(msg, k.WorkloadLogs):
+			t := m.currentWorkloadTarget()
+			if t.Name == "" {
 				return m, nil
 			}
-			if m.logsOpen {
-				m.logsOpen = false
+			m.logPrevious = false
+			return m, m.openLogs(t)
+
+		case key.Matches(msg, k.TailSelector):
+			return m, m.openPrompt(promptLogSelector, "tail selector: ", m.selector.Labels)
+
+		case key.Matches(msg, k.LabelSelector):
+			return m, m.openPrompt(promptLabelSelector, "label selector: ", m.selector.Labels)
+
+		case key.Matches(msg, k.FieldSelector):
+			return m, m.openPrompt(promptFieldSelector, "field selector: ", m.selector.Fields)
+
+		case key.Matches(msg, k.Find):
+			return m, m.openPrompt(promptFind, "/", m.find)
+
+		case key.Matches(msg, k.Command):
+			return m, m.openPrompt(promptCommand, ":", "")
+
+		case key.Matches(msg, k.Back):
+			if m.find != "" {
+				m.setFind("")
 				return m, nil
 			}
-			if m.nsPickerOpen {
-				m.nsPickerOpen = false
+			if m.exprText != "" {
+				_ = m.setFilter("")
 				return m, nil
 			}
+			if m.infoOpen {
+				m.infoOpen = false
+				return m, m.relayout()
+			}
 			if m.logsCancel != nil {
 				m.logsCancel()
 			}
-			m.nsTable.Blur()
+			m.stopSampler()
 			m.cancel()
 			return m, tea.Quit
 
-		case "s":
-			if m.sortBy == "cpu" {
-				m.sortBy = "mem"
-			} else {
-				m.sortBy = "cpu"
-			}
-			return m, m.fetch()
+		case key.Matches(msg, k.Sort):
+			m.cycleSort()
+			return m, nil
 
-		case "up", "k", "down", "j", "enter":
-			var cmd tea.Cmd
-			m.table, cmd = m.table.Update(msg)
-			return m, cmd
+		case key.Matches(msg, k.SortDirection):
+			m.flipSort()
+			return m, nil
 		}
+		return m, nil
 
 	case errMsg:
 		m.err = msg.error
@@ -378,114 +546,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) rebuildTable() {
+	var cols []widgets.Column
+	var rows []widgets.Row
+	var tints map[int]lipgloss.Style
 	switch m.view {
 	case ViewPods:
-		total := m.table.Width()
-		wPod, wCPU, wCPUBar, wMem, wMemBar, wReady, wNode, wTrend := m.podColWidths(total)
-
-		cols := []table.Column{
-			{Title: "POD (ctr)", Width: wPod},
-			{Title: "CPU", Width: wCPU},
-			{Title: "", Width: wCPUBar},
-			{Title: "MEM", Width: wMem},
-			{Title: "", Width: wMemBar},
-			{Title: "READY", Width: wReady},
-			{Title: "NODE", Width: wNode},
-			{Title: "Trend", Width: wTrend},
-		}
-
-		// Find max CPU and Mem (used for normalization fallback)
-		var maxCPU, maxMem int
-		for _, p := range m.pods {
-			if p.CPUm > maxCPU {
-				maxCPU = p.CPUm
-			}
-			if int(p.MemBytes) > maxMem {
-				maxMem = int(p.MemBytes)
-			}
-		}
-		if maxCPU == 0 {
-			maxCPU = 1
-		}
-		if maxMem == 0 {
-			maxMem = 1
-		}
-
-		var rows []table.Row
-		for _, p := range m.pods {
-			cpuNum := fmt.Sprintf("%4dm", p.CPUm)
-			memNum := fmt.Sprintf("%6.1fMi", float64(p.MemBytes)/(1024*1024))
-
-			var cpuNormBase float64
-			if p.CPUReqm > 0 {
-				cpuNormBase = float64(p.CPUReqm)
-			} else {
-				cpuNormBase = float64(maxCPU)
-			}
-			cpuBar := widgets.Bar(float64(p.CPUm)/cpuNormBase, wCPUBar-1)
-
-			var memNormBase float64
-			if p.MemReqBytes > 0 {
-				memNormBase = float64(p.MemReqBytes)
-			} else {
-				memNormBase = float64(maxMem)
-			}
-			memBar := widgets.Bar(float64(p.MemBytes)/memNormBase, wMemBar-1)
-
-			rows = append(rows, table.Row{
-				fmt.Sprintf("%s (%s)", p.PodName, p.Container),
-				cpuNum,
-				cpuBar,
-				memNum,
-				memBar,
-				p.Ready,
-				p.NodeName,
-				widgets.Spark8(p.CPUTrend.Samples, wTrend),
-			})
-		}
-		m.table.SetColumns(cols)
-		m.table.SetRows(rows)
-		m.table.Focus()
-
+		cols, rows = m.podTable()
+		tints = m.podTints()
 	case ViewNodes:
-		total := m.table.Width()
-		wNode, wCPUP, wCPUBar, wMEMP, wMEMBar, wPods, wK8s, wTrend := m.nodeColWidths(total)
-
-		cols := []table.Column{
-			{Title: "NODE", Width: wNode},
-			{Title: "CPU%", Width: wCPUP},
-			{Title: "", Width: wCPUBar},
-			{Title: "MEM%", Width: wMEMP},
-			{Title: "", Width: wMEMBar},
-			{Title: "PODS", Width: wPods},
-			{Title: "K8S", Width: wK8s},
-			{Title: "Trend", Width: wTrend},
-		}
-		var rows []table.Row
-		for _, n := range m.nodes {
-			cpuPct := fmt.Sprintf("%3.0f%%", n.CPUUsed*100)
-			memPct := fmt.Sprintf("%3.0f%%", n.MEMUsed*100)
-			cpuBar := widgets.Bar(n.CPUUsed, wCPUBar-1)
-			memBar := widgets.Bar(n.MEMUsed, wMEMBar-1)
-			trend := widgets.Spark8(n.CPUTrend.Samples, wTrend)
-			if trend == "" {
-				trend = "—"
-			}
-			rows = append(rows, table.Row{
-				n.NodeName,
-				cpuPct,
-				cpuBar,
-				memPct,
-				memBar,
-				fmt.Sprintf("%d", n.Pods),
-				n.K8sVer,
-				trend,
-			})
-		}
-		m.table.SetColumns(cols)
-		m.table.SetRows(rows)
-		m.table.Focus()
+		cols, rows = m.nodeTable()
+		tints = m.nodeTints()
 	}
+	m.markSorted(cols)
+	m.table.SetColumns(cols)
+	m.table.SetRows(rows)
+	m.table.SetTints(tints)
+	m.table.Focus()
 }
 
 func (m Model) currentSelection() int {
@@ -504,70 +580,182 @@ func (m Model) currentLogsTarget() domain.LogsTarget {
 			return domain.LogsTarget{Namespace: m.ns, Kind: "Pod", Name: ""}
 		}
 		p := m.pods[i%len(m.pods)]
-		return domain.LogsTarget{Namespace: m.ns, Kind: "Pod", Name: p.PodName, Container: p.Container}
+		return domain.LogsTarget{Namespace: p.Namespace, Kind: "Pod", Name: p.PodName, Container: p.Container}
 	case ViewNodes:
-		i := m.currentSelection()
-		if len(m.nodes) == 0 {
+		if p, ok := m.selectedNodePod(); ok {
+			return domain.LogsTarget{Namespace: p.Namespace, Kind: "Pod", Name: p.PodName, Container: p.Container}
+		}
+		n, ok := m.selectedNode()
+		if !ok {
 			return domain.LogsTarget{Kind: "Node"}
 		}
-		n := m.nodes[i%len(m.nodes)]
 		return domain.LogsTarget{Kind: "Node", Name: n.NodeName}
 	default:
 		return domain.LogsTarget{}
 	}
 }
 
+// currentWorkloadTarget tails every pod of the selected pod's owner; bare
+// pods fall back to their own logs.
+func (m Model) currentWorkloadTarget() domain.LogsTarget {
+	if m.view != ViewPods || len(m.pods) == 0 {
+		return m.currentLogsTarget()
+	}
+	p := m.pods[m.currentSelection()%len(m.pods)]
+	if p.OwnerKind == "" {
+		return m.currentLogsTarget()
+	}
+	return domain.LogsTarget{Namespace: p.Namespace, Kind: p.OwnerKind, Name: p.OwnerName}
+}
+
 func (m Model) consumeLogs(ctx context.Context, t domain.LogsTarget) tea.Cmd {
 	return func() tea.Msg {
 		ch, err := m.repoL.StreamLogs(ctx, t)
 		if err != nil {
 			return errMsg{err}
 		}
-		m.logCh = ch
-		m.logsVP.SetContent("")
-		m.logBuf.Reset()
-		return readNextLog(ch)()
+		return logStreamMsg{ch}
+	}
+}
+
+// nodeEvents is the picker entry for the node's events instead of a log.
+const nodeEvents = "events"
+
+// fetchNodeLogServices asks the repo what node logs exist; repos without node
+// log support only offer events.
+func (m Model) fetchNodeLogServices(node string) tea.Cmd {
+	return func() tea.Msg {
+		items := []string{nodeEvents}
+		if src, ok := m.repoL.(domain.NodeLogSources); ok {
+			if svcs, err := src.NodeLogServices(m.ctx, node); err == nil {
+				items = append(svcs, nodeEvents)
+			}
+		}
+		return nodeLogServicesMsg{node, items}
 	}
 }
 
+// switchView flips between Pods and Nodes, closing the panes.
+func (m *Model) switchView() tea.Cmd {
+	if m.view == ViewPods {
+		m.view = ViewNodes
+	} else {
+		m.view = ViewPods
+	}
+	m.infoOpen = false
+	m.closeLogs()
+	m.autoCursor = true
+	return m.fetch()
+}
+
+// paneBase is the rows the table and the panes under it share.
+func (m Model) paneBase() int {
+	// compute vertical layout using measured header/footer, not magic numbers
+	headerH := lipgloss.Height(styles.Header.Render("x"))
+	footerH := lipgloss.Height(styles.Footer.Render("x"))
+	return max(10, m.height-headerH-footerH-2) // top/bot padding
+}
+
+// layout sizes the table and the logs pane to the window. With logs open
+// the table gets tableShare of what's left (dragging the border moves it),
+// the info panel what it needs, see infoLines.
+func (m *Model) layout() {
+	base := m.paneBase()
+	share := m.tableShare
+	if share == 0 {
+		share = 0.55
+	}
+	switch {
+	case m.infoOpen && m.logsOpen:
+		rest := max(10, base-m.infoLines()-2)
+		m.table.SetHeight(clamp(int(float64(rest)*share), 3, rest-3))
+		m.logsVP.Width = m.width - 4
+		m.logsVP.Height = rest - m.table.Height()
+
+	case m.logsOpen:
+		m.table.SetHeight(clamp(int(float64(base)*share), 3, base-3))
+		m.logsVP.Width = m.width - 4
+		m.logsVP.Height = base - m.table.Height()
+
+	case m.infoOpen:
+		// the info panel takes what it needs, see infoLines, plus its border
+		m.table.SetHeight(max(5, base-m.infoLines()-2))
+		m.logsVP.Width = m.width - 4
+		m.logsVP.Height = 0
+
+	default:
+		m.table.SetHeight(base)
+		m.logsVP.Width = m.width - 4
+		m.logsVP.Height = 0
+	}
+	if m.dash.on {
+		m.sizeDashLogs()
+	}
+	m.flushLogs()
+	// table target width = terminal width minus side padding/borders
+	m.table.SetWidth(m.width - 4)
+
+	// rebuild columns with new widths
+	m.rebuildTable()
+}
+
+// relayout triggers a synthetic resize so pane heights are recalculated.
+func (m Model) relayout() tea.Cmd {
+	return func() tea.Msg { return tea.WindowSizeMsg{Width: m.width, Height: m.height} }
+}
+
+func (m Model) headerLine() string {
+	return fmt.Sprintf("kmet v0.x  │ ctx: %s  ns: %s%s  view: %s  sort: %s  (Tab switch Pods/Nodes)  [?]help [q]quit",
+		config.Or(m.kctx, "-"), m.ns, selectorLabel(m.selector), map[View]string{ViewPods: "Pods", ViewNodes: "Nodes"}[m.view], m.sortLabel())
+}
+
 func (m Model) View() string {
-	head := styles.Header.Render(
-		fmt.Sprintf("kmet v0.x  │ ctx: dev  ns: %s  view: %s  sort: %s  (Tab switch Pods/Nodes)  [i]info [s]sort [q]quit",
-			m.ns, map[View]string{ViewPods: "Pods", ViewNodes: "Nodes"}[m.view], m.sortBy),
-	)
+	head := styles.Header.Render(m.headerLine())
 	body := lipgloss.NewStyle().Padding(0, 1).Render(m.table.View())
 
 	info := ""
 	if m.infoOpen {
-		info = styles.Box.Width(m.width - 2).Render(m.renderInfo())
+		info = styles.Box.Width(m.width - 2).Height(m.infoLines()).MaxHeight(m.infoLines() + 2).Render(m.renderInfo())
 	}
 
 	logs := ""
 	if m.logsOpen {
-		logs = styles.Box.Width(m.width - 2).Render("Logs:\n" + m.logsVP.View())
+		logs = styles.Box.Width(m.width - 2).Render(m.logsTitle() + "\n" + m.logsVP.View())
 	}
 
-	// Overlay picker
-	overlay := ""
-	if m.nsPickerOpen {
-		box := styles.Box.
-			BorderForeground(lipgloss.Color("#7DCE13")).
-			Width(40).Height(14)
-		title := styles.Title.Render(" Switch Namespace (↑/↓, Enter, Esc) ")
-		content := lipgloss.JoinVertical(lipgloss.Left,
-			title,
-			m.nsTable.View(),
-		)
-		overlay = lipgloss.Place(m.width, m.height,
-			lipgloss.Center, lipgloss.Center,
-			box.Render(content),
-		)
+	footer := m.footerHelp()
+	if !m.logsOpen && (m.find != "" || m.exprText != "") {
+		footer = styles.Footer.Render(m.filterStatus())
+	}
+	if m.status != "" {
+		footer = m.status
+	}
+	if m.promptKind != promptNone {
+		footer = m.prompt.View()
+		if m.promptErr != "" {
+			footer += "  " + styles.Danger.Render(m.promptErr)
+		}
 	}
-	footer := styles.Footer.Render("↑/↓ move • [Tab] switch view • [n] namespace • [i] info • [s] sort • [q] quit")
 
 	main := lipgloss.JoinVertical(lipgloss.Left, head, body, info, logs, footer)
-	if m.nsPickerOpen {
-		return main + "\n" + overlay
+	switch {
+	case m.detail.open():
+		main = lipgloss.JoinVertical(lipgloss.Left, head, m.detailView(), footer)
+	case m.heat.on:
+		main = lipgloss.JoinVertical(lipgloss.Left, head, m.heatmapView(), footer)
+	case m.tree.on:
+		main = lipgloss.JoinVertical(lipgloss.Left, head, m.treemapView(), footer)
+	case m.dash.on:
+		main = lipgloss.JoinVertical(lipgloss.Left, head, m.dashboardView(), footer)
+	}
+	if m.picker.open() {
+		return main + "\n" + m.picker.view(m.width, m.height)
+	}
+	if m.chooser.open() {
+		return main + "\n" + m.chooser.render(m.width, m.height, m.chooserHelp())
+	}
+	if m.helpMode != 0 {
+		return main + "\n" + m.renderHelp(m.width, m.height)
 	}
 	return main
 }
@@ -602,6 +790,8 @@ func (m Model) renderInfo() string {
 		// Utilization vs Request
 		utilCPUReq := float64(p.CPUm) / float64(max(1, p.CPUReqm))
 		utilMemReq := float64(p.MemBytes) / float64(max64(1, p.MemReqBytes))
+		cpuReq := m.severity("cpu/req", float64(p.CPUm), float64(p.CPUReqm))
+		memReq := m.severity("mem/req", float64(p.MemBytes), float64(p.MemReqBytes))
 
 		// Utilization vs Max
 		utilCPUMax := float64(p.CPUm) / float64(maxCPU)
@@ -609,67 +799,63 @@ func (m Model) renderInfo() string {
 
 		return fmt.Sprintf(
 			`Pod: %s  ns: %s  node: %s  phase: %s
-Image: ghcr.io/acme/%s:mock
-Requests: cpu=%dm mem=%dMi  Ready: %s
-
-Util vs Req: CPU %3.0f%% %s  MEM %3.0f%% %s
-Util vs Max: CPU %3.0f%% %s  MEM %3.0f%% %s
-
-Trend CPU: %s
-Trend MEM: %s`,
-			p.PodName, p.Namespace, p.NodeName, p.Phase, p.Container,
-			p.CPUReqm, p.MemReqBytes/(1024*1024), p.Ready,
-			utilCPUReq*100, widgets.Bar(math.Min(utilCPUReq, 1), 20),
-			utilMemReq*100, widgets.Bar(math.Min(utilMemReq, 1), 20),
-			utilCPUMax*100, widgets.Bar(utilCPUMax, 20),
-			utilMemMax*100, widgets.Bar(utilMemMax, 20),
-			widgets.Spark8(p.CPUTrend.Samples, 30),
-			widgets.Spark8(p.MemTrend.Samples, 30),
+QoS: %s  owner: %s  restarts: %d  %s
+Requests: cpu=%dm mem=%dMi  Ready: %s%s
+
+Util vs Req: CPU %s %s  MEM %s %s
+Util vs Max: CPU %3.0f%% %s  MEM %3.0f%% %s%s
+
+%s`,
+			p.PodName, p.Namespace, p.NodeName, p.Phase,
+			dash(p.QoS), dash(strings.TrimPrefix(p.OwnerKind+"/"+p.OwnerName, "/")), p.Restarts,
+			styles.Faint.Render("("+m.keys.Detail.Help().Key+": details)"),
+			p.CPUReqm, p.MemReqBytes/(1024*1024), p.Ready, conditionNote(p.NodePressure),
+			cpuReq.paint(fmt.Sprintf("%3.0f%%", utilCPUReq*100)), cpuReq.bar(math.Min(utilCPUReq, 1), 20),
+			memReq.paint(fmt.Sprintf("%3.0f%%", utilMemReq*100)), memReq.bar(math.Min(utilMemReq, 1), 20),
+			utilCPUMax*100, bar(utilCPUMax, 20),
+			utilMemMax*100, bar(utilMemMax, 20),
+			m.errTrend(p, 30),
+			m.podCharts(p),
 		)
 
 	case ViewNodes:
-		i := m.currentSelection()
-		if len(m.nodes) == 0 {
+		n, ok := m.selectedNode()
+		if !ok {
 			return "No nodes"
 		}
-		n := m.nodes[i%len(m.nodes)]
+		conds := n.Pressure
+		if n.NotReady {
+			conds = append([]string{"NotReady"}, conds...)
+		}
 		return fmt.Sprintf(
-			"Node: %s  k8s: %s  pods: %d\nCPU(5m): %s\nMEM(5m): %s",
-			n.NodeName, n.K8sVer, n.Pods,
-			widgets.Spark8(n.CPUTrend.Samples, 40),
-			widgets.Spark8(n.MEMTrend.Samples, 40),
+			"Node: %s  k8s: %s  pods: %d%s\n\n%s",
+			n.NodeName, n.K8sVer, n.Pods, conditionNote(conds),
+			m.nodeCharts(n),
 		)
 	default:
 		return ""
 	}
 }
 
-func sortPods(p []domain.PodMetric, by string) {
-	for i := 0; i < len(p); i++ {
-		for j := 0; j < len(p)-1; j++ {
-			less := p[j].CPUm < p[j+1].CPUm
-			if by == "mem" {
-				less = p[j].MemBytes < p[j+1].MemBytes
-			}
-			if less {
-				p[j], p[j+1] = p[j+1], p[j]
-			}
-		}
+// infoLines is the info panel's height inside its border: the pod or
+// node text, the ERR/min line when sampling, and the charts.
+func (m Model) infoLines() int {
+	if m.view == ViewNodes {
+		return 3 + m.chartRows()
+	}
+	n := 8 + m.chartRows()
+	if m.errRate != nil {
+		n++
 	}
+	return n
 }
 
-func sortNodes(n []domain.NodeMetric, by string) {
-	for i := 0; i < len(n); i++ {
-		for j := 0; j < len(n)-1; j++ {
-			less := n[j].CPUUsed < n[j+1].CPUUsed
-			if by == "mem" {
-				less = n[j].MEMUsed < n[j+1].MEMUsed
-			}
-			if less {
-				n[j], n[j+1] = n[j+1], n[j]
-			}
-		}
+// conditionNote lists node conditions worth a look, for the info panel.
+func conditionNote(conds []string) string {
+	if len(conds) == 0 {
+		return ""
 	}
+	return "  " + styles.Warn.Render("node: "+strings.Join(conds, ", "))
 }
 
 func max(a, b int) int {
@@ -0,0 +1,94 @@
+// internal/ui/app/charts.go
+package app
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/styles"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/widgets"
+)
+
+// chartHeight is the rows of each info panel chart, axes and legend
+// included.
+const chartHeight = 8
+
+// chartRows is the info panel charts' height, as dragged.
+func (m Model) chartRows() int { return max(4, chartHeight+m.infoGrow) }
+
+// trendChart plots a trend in its own unit.
+func trendChart(name string, t domain.Trend, yFmt func(float64) string, w int) widgets.LineChart {
+	return widgets.LineChart{
+		Width: w, Height: chartHeight,
+		Series:  []widgets.Series{{Name: name, Values: t.Values, Times: t.Times, Style: styles.Spark}},
+		YFormat: yFmt,
+		Axis:    styles.Faint,
+	}
+}
+
+// bounds are the request and limit reference lines, as far as they're set.
+func bounds(req, lim float64) []widgets.RefLine {
+	var refs []widgets.RefLine
+	if req > 0 {
+		refs = append(refs, widgets.RefLine{Name: "request", Value: req, Style: styles.Warn})
+	}
+	if lim > 0 {
+		refs = append(refs, widgets.RefLine{Name: "limit", Value: lim, Style: styles.Danger})
+	}
+	return refs
+}
+
+// pair lays two titled charts side by side.
+func pair(titleA string, a widgets.LineChart, titleB string, b widgets.LineChart) string {
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		styles.Title.Render(titleA)+"\n"+a.View(), "  ",
+		styles.Title.Render(titleB)+"\n"+b.View())
+}
+
+// chartWidth fits two charts and their gap in the info panel.
+func (m Model) chartWidth() int { return max(20, (m.width-8)/2) }
+
+func (m Model) podCharts(p domain.PodMetric) string {
+	cpu := trendChart("cpu", p.CPUTrend, cores, m.chartWidth())
+	cpu.Refs = bounds(float64(p.CPUReqm), float64(p.CPULimm))
+	mem := trendChart("mem", p.MemTrend, bytesIEC, m.chartWidth())
+	mem.Refs = bounds(float64(p.MemReqBytes), float64(p.MemLimBytes))
+	mem.Binary = true
+	cpu.Height, mem.Height = m.chartRows(), m.chartRows()
+	return pair("CPU", cpu, "MEM", mem)
+}
+
+func (m Model) nodeCharts(n domain.NodeMetric) string {
+	cpu := trendChart("cpu", n.CPUTrend, share, m.chartWidth())
+	mem := trendChart("mem", n.MEMTrend, share, m.chartWidth())
+	cpu.Max, mem.Max = 1, 1
+	cpu.Height, mem.Height = m.chartRows(), m.chartRows()
+	return pair("CPU of allocatable", cpu, "MEM of allocatable", mem)
+}
+
+// cores, bytesIEC and share are chart Y labels.
+func cores(m float64) string {
+	switch {
+	case m == 0:
+		return "0"
+	case m >= 1000:
+		return fmt.Sprintf("%.3g", m/1000)
+	}
+	return fmt.Sprintf("%.0fm", m)
+}
+
+func bytesIEC(b float64) string {
+	switch {
+	case b == 0:
+		return "0"
+	case b >= 1<<30:
+		return fmt.Sprintf("%.3gGi", b/(1<<30))
+	case b >= 1<<20:
+		return fmt.Sprintf("%.0fMi", b/(1<<20))
+	}
+	return fmt.Sprintf("%.0fKi", b/(1<<10))
+}
+
+func share(v float64) string { return fmt.Sprintf("%.0f%%", v*100) }
@@ -0,0 +1,208 @@
+// internal/ui/app/chooser.go
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/config"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/styles"
+)
+
+// chooser is the column chooser overlay: every column of a view, the shown
+// ones first and in order, each with a checkbox and an optional fixed width.
+type chooser struct {
+	target View
+	items  []chooserItem
+	cursor int
+	err    string
+}
+
+type chooserItem struct {
+	config.Column
+	title string
+	min   int // smallest width "-" goes to before switching back to auto
+	on    bool
+}
+
+func (m Model) newChooser(cur []config.Column) chooser {
+	c := chooser{target: m.view}
+	seen := map[string]bool{}
+	add := func(col config.Column, on bool) {
+		if seen[col.Name] {
+			return
+		}
+		seen[col.Name] = true
+		it := chooserItem{Column: col, on: on}
+		if m.view == ViewNodes {
+			nc := nodeColumns[col.Name]
+			it.title, it.min = nc.title, nc.min
+		} else {
+			pc, _ := podColumnFor(col.Name)
+			it.title, it.min = pc.title, pc.min
+		}
+		c.items = append(c.items, it)
+	}
+
+	// shown columns, then the rest of the registry, then labels seen on pods
+	for _, col := range cur {
+		add(col, true)
+	}
+	if m.view == ViewNodes {
+		for _, n := range sortedNames(nodeColumns) {
+			add(config.Column{Name: n}, false)
+		}
+		return c
+	}
+	for _, n := range sortedNames(podColumns) {
+		add(config.Column{Name: n}, false)
+	}
+	keys := map[string]bool{}
+	for _, p := range m.allPods {
+		for k := range p.Labels {
+			keys[k] = true
+		}
+	}
+	for _, k := range sortedNames(keys) {
+		add(config.Column{Name: labelPrefix + k}, false)
+	}
+	return c
+}
+
+func (c chooser) open() bool { return c.items != nil }
+
+// columns is the chosen layout: checked items, in order.
+func (c chooser) columns() []config.Column {
+	var out []config.Column
+	for _, it := range c.items {
+		if it.on {
+			out = append(out, it.Column)
+		}
+	}
+	return out
+}
+
+func (c *chooser) move(d int) {
+	j := c.cursor + d
+	if j < 0 || j >= len(c.items) {
+		return
+	}
+	c.items[c.cursor], c.items[j] = c.items[j], c.items[c.cursor]
+	c.cursor = j
+}
+
+// resize changes the fixed width of the current item; below its minimum
+// the column goes back to being sized automatically.
+func (c *chooser) resize(d int) {
+	it := &c.items[c.cursor]
+	switch {
+	case it.Width == 0 && d > 0:
+		it.Width = it.min
+	case it.Width+d < it.min:
+		it.Width = 0
+	default:
+		it.Width += d
+	}
+}
+
+func (c chooser) render(width, height int, help string) string {
+	name := "pods"
+	if c.target == ViewNodes {
+		name = "nodes"
+	}
+	h := clamp(height-10, 5, len(c.items))
+	start := clamp(c.cursor-h/2, 0, len(c.items)-h)
+
+	lines := []string{styles.Title.Render(" Columns: " + name + " ")}
+	for i := start; i < start+h; i++ {
+		it := c.items[i]
+		box, w := "[ ]", "auto"
+		if it.on {
+			box = "[x]"
+		}
+		if it.Width > 0 {
+			w = fmt.Sprint(it.Width)
+		}
+		line := fmt.Sprintf("%s %-22s %-10s %4s", box, it.Name, it.title, w)
+		if i == c.cursor {
+			line = styles.TabActive.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+	if c.err != "" {
+		help = styles.Danger.Render(c.err)
+	}
+	lines = append(lines, "", help)
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center,
+		styles.Popup.Render(strings.Join(lines, "\n")))
+}
+
+// updateChooser handles keys while the column chooser is open.
+func (m Model) updateChooser(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := &m.chooser
+	c.err = ""
+	k := m.keys
+	n, _ := m.popCount()
+	switch {
+	case key.Matches(msg, k.Back):
+		m.chooser = chooser{}
+	case key.Matches(msg, k.Up):
+		c.cursor = max(0, c.cursor-n)
+	case key.Matches(msg, k.Down):
+		c.cursor = min(len(c.items)-1, c.cursor+n)
+	case key.Matches(msg, k.Toggle):
+		c.items[c.cursor].on = !c.items[c.cursor].on
+	case key.Matches(msg, k.MoveUp):
+		c.move(-1)
+	case key.Matches(msg, k.MoveDown):
+		c.move(1)
+	case key.Matches(msg, k.Wider):
+		c.resize(1)
+	case key.Matches(msg, k.Narrower):
+		c.resize(-1)
+	case key.Matches(msg, k.Reset):
+		names := defaultPodColumns
+		if c.target == ViewNodes {
+			names = defaultNodeColumns
+		}
+		m.chooser = m.newChooser(columnsOf(names))
+	case key.Matches(msg, k.Select, k.Save):
+		cols := c.columns()
+		if len(cols) == 0 {
+			c.err = "pick at least one column"
+			return m, nil
+		}
+		m.setColumns(cols)
+		m.chooser = chooser{}
+		if key.Matches(msg, k.Save) {
+			view := "pods"
+			if m.view == ViewNodes {
+				view = "nodes"
+			}
+			// into the context's own columns if it overrides them
+			kctx := ""
+			if o := m.cfg.Contexts[m.kctx]; len(o.Columns.Pods)+len(o.Columns.Nodes) > 0 {
+				kctx = m.kctx
+			}
+			if err := config.SaveColumns(m.cfgPath, kctx, view, cols); err != nil {
+				m.status = styles.Danger.Render("save columns: " + err.Error())
+			} else {
+				m.status = fmt.Sprintf("saved %s columns to %s", view, m.cfgPath)
+			}
+		}
+	}
+	return m, nil
+}
+
+// setColumns replaces the current view's columns and redraws the table.
+func (m *Model) setColumns(cols []config.Column) {
+	*m.viewColumns() = cols
+	m.rebuildTable()
+}
@@ -0,0 +1,372 @@
+// internal/ui/app/columns.go
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/config"
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/styles"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/widgets"
+)
+
+// colLayout is how a column takes part in width allocation. Widths are in
+// cells, without the table's cell padding.
+type colLayout struct {
+	min, max int // max == min: fixed width
+	flex     int // share of spare width once every column has its minimum
+	drop     int // when the terminal is too narrow, highest drop goes first; 0 never
+}
+
+// podRow is what pod cells are rendered from: the pod plus table-wide
+// values, like the largest usage that bars fall back to without a request.
+type podRow struct {
+	m      *Model
+	p      *domain.PodMetric
+	maxCPU int
+	maxMem int64
+}
+
+type podColumn struct {
+	title string
+	colLayout
+	cell func(r podRow, w int) string
+}
+
+type nodeColumn struct {
+	title string
+	colLayout
+	cell func(m *Model, n *domain.NodeMetric, w int) string
+}
+
+var podColumns = map[string]podColumn{
+	"pod":       {"POD (ctr)", colLayout{16, 60, 3, 0}, func(r podRow, _ int) string { return highlight(podCell(*r.p), r.m.find) }},
+	"container": {"CONTAINER", colLayout{10, 24, 1, 3}, func(r podRow, _ int) string { return r.p.Container }},
+	"namespace": {"NAMESPACE", colLayout{10, 24, 1, 3}, func(r podRow, _ int) string { return r.p.Namespace }},
+	"cpu":       {"CPU", colLayout{6, 6, 0, 0}, func(r podRow, _ int) string { return r.m.podCPU(r.p).paint(fmt.Sprintf("%4dm", r.p.CPUm)) }},
+	"cpu.bar": {"", colLayout{6, 40, 2, 5}, func(r podRow, w int) string {
+		return r.m.podCPU(r.p).bar(float64(r.p.CPUm)/float64(config.Or(r.p.CPUReqm, r.maxCPU)), w-1)
+	}},
+	"cpu.req": {"CPU REQ", colLayout{7, 7, 0, 3}, func(r podRow, _ int) string { return milli(r.p.CPUReqm) }},
+	"cpu.lim": {"CPU LIM", colLayout{7, 7, 0, 3}, func(r podRow, _ int) string { return milli(r.p.CPULimm) }},
+	"cpu/node": {"CPU/NODE", colLayout{8, 8, 0, 3}, func(r podRow, _ int) string {
+		return r.m.severity("cpu/node", float64(r.p.CPUm), float64(r.p.NodeCPUm)).paint(percent(float64(r.p.CPUm), float64(r.p.NodeCPUm)))
+	}},
+	"mem": {"MEM", colLayout{8, 8, 0, 0}, func(r podRow, _ int) string {
+		return r.m.podMem(r.p).paint(fmt.Sprintf("%6.1fMi", float64(r.p.MemBytes)/(1024*1024)))
+	}},
+	"mem.bar": {"", colLayout{6, 40, 2, 5}, func(r podRow, w int) string {
+		return r.m.podMem(r.p).bar(float64(r.p.MemBytes)/float64(config.Or(r.p.MemReqBytes, r.maxMem)), w-1)
+	}},
+	"mem.req": {"MEM REQ", colLayout{8, 8, 0, 3}, func(r podRow, _ int) string { return mebi(r.p.MemReqBytes) }},
+	"mem.lim": {"MEM LIM", colLayout{8, 8, 0, 3}, func(r podRow, _ int) string { return mebi(r.p.MemLimBytes) }},
+	"mem/node": {"MEM/NODE", colLayout{8, 8, 0, 3}, func(r podRow, _ int) string {
+		return r.m.severity("mem/node", float64(r.p.MemBytes), float64(r.p.NodeMemBytes)).paint(percent(float64(r.p.MemBytes), float64(r.p.NodeMemBytes)))
+	}},
+	"err":      {"ERR/min", colLayout{7, 7, 0, 2}, func(r podRow, _ int) string { return r.m.errCell(*r.p) }},
+	"ready":    {"READY", colLayout{6, 6, 0, 1}, func(r podRow, _ int) string { return r.p.Ready }},
+	"restarts": {"RESTARTS", colLayout{8, 8, 0, 2}, func(r podRow, _ int) string { return fmt.Sprint(r.p.Restarts) }},
+	"age":      {"AGE", colLayout{5, 5, 0, 2}, func(r podRow, _ int) string { return podAge(r.p.Created) }},
+	"phase":    {"PHASE", colLayout{9, 9, 0, 2}, func(r podRow, _ int) string { return r.p.Phase }},
+	"qos":      {"QOS", colLayout{10, 10, 0, 3}, func(r podRow, _ int) string { return dash(r.p.QoS) }},
+	"owner":    {"OWNER", colLayout{10, 30, 1, 3}, func(r podRow, _ int) string { return dash(r.p.OwnerName) }},
+	"node":     {"NODE", colLayout{12, 30, 1, 2}, func(r podRow, _ int) string { return highlight(r.p.NodeName, r.m.find) }},
+	"trend":    {"Trend", colLayout{8, 8, 0, 4}, func(r podRow, w int) string { return spark(r.p.CPUTrend.Samples, w) }},
+	"mem.trend": {"MEM trend", colLayout{9, 9, 0, 4}, func(r podRow, w int) string {
+		return spark(r.p.MemTrend.Samples, w)
+	}},
+}
+
+var nodeColumns = map[string]nodeColumn{
+	"name": {"NODE", colLayout{12, 40, 1, 0}, func(m *Model, n *domain.NodeMetric, _ int) string { return highlight(n.NodeName, m.find) }},
+	"cpu": {"CPU%", colLayout{6, 6, 0, 0}, func(m *Model, n *domain.NodeMetric, _ int) string {
+		return m.severity("node.cpu", n.CPUUsed, 1).paint(percent(n.CPUUsed, 1))
+	}},
+	"cpu.bar": {"", colLayout{6, 40, 1, 5}, func(m *Model, n *domain.NodeMetric, w int) string {
+		return m.severity("node.cpu", n.CPUUsed, 1).bar(n.CPUUsed, w-1)
+	}},
+	"mem": {"MEM%", colLayout{6, 6, 0, 0}, func(m *Model, n *domain.NodeMetric, _ int) string {
+		return m.severity("node.mem", n.MEMUsed, 1).paint(percent(n.MEMUsed, 1))
+	}},
+	"mem.bar": {"", colLayout{6, 40, 1, 5}, func(m *Model, n *domain.NodeMetric, w int) string {
+		return m.severity("node.mem", n.MEMUsed, 1).bar(n.MEMUsed, w-1)
+	}},
+	"cpu.req": {"CPU REQ", colLayout{7, 7, 0, 3}, func(_ *Model, n *domain.NodeMetric, _ int) string {
+		return percent(float64(n.CPUReqm), float64(n.CPUAllocm))
+	}},
+	"mem.req": {"MEM REQ", colLayout{7, 7, 0, 3}, func(_ *Model, n *domain.NodeMetric, _ int) string {
+		return percent(float64(n.MemReqBytes), float64(n.MemAllocBytes))
+	}},
+	"pods":    {"PODS", colLayout{5, 5, 0, 1}, func(_ *Model, n *domain.NodeMetric, _ int) string { return fmt.Sprint(n.Pods) }},
+	"version": {"K8S", colLayout{6, 20, 0, 3}, func(_ *Model, n *domain.NodeMetric, _ int) string { return n.K8sVer }},
+	"trend":   {"Trend", colLayout{8, 8, 0, 4}, func(_ *Model, n *domain.NodeMetric, w int) string { return dash(spark(n.CPUTrend.Samples, w)) }},
+	"mem.trend": {"MEM trend", colLayout{9, 9, 0, 4}, func(_ *Model, n *domain.NodeMetric, w int) string {
+		return dash(spark(n.MEMTrend.Samples, w))
+	}},
+}
+
+// labelPrefix makes a pod label a column: "label:app".
+const labelPrefix = "label:"
+
+var (
+	defaultPodColumns  = []string{"pod", "cpu", "cpu.bar", "mem", "mem.bar", "err", "ready", "node", "trend"}
+	defaultNodeColumns = []string{"name", "cpu", "cpu.bar", "mem", "mem.bar", "cpu.req", "mem.req", "pods", "version", "trend"}
+)
+
+// podColumnFor resolves a column name, including label columns.
+func podColumnFor(name string) (podColumn, bool) {
+	if key, ok := strings.CutPrefix(name, labelPrefix); ok && key != "" {
+		title := strings.ToUpper(key[strings.LastIndex(key, "/")+1:])
+		return podColumn{title, colLayout{8, 30, 1, 3}, func(r podRow, _ int) string { return dash(r.p.Labels[key]) }}, true
+	}
+	c, ok := podColumns[name]
+	return c, ok
+}
+
+func sortedNames[V any](reg map[string]V) []string {
+	names := make([]string, 0, len(reg))
+	for n := range reg {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkColumns reports configured columns that don't exist.
+func checkColumns(path string, c config.Columns) error {
+	for _, col := range c.Pods {
+		if _, ok := podColumnFor(col.Name); !ok {
+			return fmt.Errorf("%s.pods: unknown column %q (have: %s, %sKEY)",
+				path, col.Name, strings.Join(sortedNames(podColumns), ", "), labelPrefix)
+		}
+	}
+	for _, col := range c.Nodes {
+		if _, ok := nodeColumns[col.Name]; !ok {
+			return fmt.Errorf("%s.nodes: unknown column %q (have: %s)",
+				path, col.Name, strings.Join(sortedNames(nodeColumns), ", "))
+		}
+	}
+	return nil
+}
+
+func columnsOf(names []string) []config.Column {
+	cols := make([]config.Column, len(names))
+	for i, n := range names {
+		cols[i] = config.Column{Name: n}
+	}
+	return cols
+}
+
+// viewColumns is the current view's chosen columns.
+func (m *Model) viewColumns() *[]config.Column {
+	if m.view == ViewNodes {
+		return &m.nodeCols
+	}
+	return &m.podCols
+}
+
+// layout sizes columns to fit total cells. Configured widths are kept as is;
+// the rest start at their minimum and share what's left by flex. If even
+// the minimums don't fit, columns are dropped (width 0) in drop order.
+func layout(cols []colLayout, fixed []int, total int) []int {
+	const pad = 2 // cell padding, see widgets.DefaultTableStyles
+	w := make([]int, len(cols))
+	used := 0
+	for i, c := range cols {
+		w[i] = c.min
+		if fixed[i] > 0 {
+			w[i] = fixed[i]
+		}
+		used += w[i] + pad
+	}
+	for used > total {
+		worst := -1
+		for i, c := range cols {
+			if w[i] > 0 && c.drop > 0 && (worst < 0 || c.drop >= cols[worst].drop) {
+				worst = i
+			}
+		}
+		if worst < 0 {
+			break
+		}
+		used -= w[worst] + pad
+		w[worst] = 0
+	}
+
+	// Hand out spare cells by flex until every flexible column is full.
+	for spare := total - used; spare > 0; {
+		flex := 0
+		for i, c := range cols {
+			if w[i] > 0 && fixed[i] == 0 && w[i] < c.max {
+				flex += c.flex
+			}
+		}
+		if flex == 0 {
+			break
+		}
+		given := 0
+		for i, c := range cols {
+			if w[i] == 0 || fixed[i] > 0 || w[i] >= c.max || c.flex == 0 {
+				continue
+			}
+			add := min(max(1, spare*c.flex/flex), c.max-w[i], spare-given)
+			w[i] += add
+			given += add
+		}
+		if given == 0 {
+			break
+		}
+		spare -= given
+	}
+	return w
+}
+
+// podTable builds the pod table's columns and rows from m.podCols.
+func (m *Model) podTable() ([]widgets.Column, []widgets.Row) {
+	var specs []podColumn
+	var lays []colLayout
+	var fixed []int
+	for _, c := range m.podCols {
+		pc, ok := podColumnFor(c.Name)
+		if !ok || (c.Name == "err" && m.errRate == nil) {
+			continue
+		}
+		specs = append(specs, pc)
+		lays = append(lays, pc.colLayout)
+		fixed = append(fixed, c.Width)
+	}
+	widths := layout(lays, fixed, m.table.Width())
+
+	cols := make([]widgets.Column, len(specs))
+	for i, s := range specs {
+		cols[i] = widgets.Column{Title: s.title, Width: widths[i]}
+	}
+
+	// Bars fall back to the largest usage in view when there's no request.
+	r := podRow{m: m, maxCPU: 1, maxMem: 1}
+	for _, p := range m.pods {
+		r.maxCPU = max(r.maxCPU, p.CPUm)
+		r.maxMem = max64(r.maxMem, p.MemBytes)
+	}
+	rows := make([]widgets.Row, 0, len(m.pods))
+	for i := range m.pods {
+		r.p = &m.pods[i]
+		row := make(widgets.Row, len(specs))
+		for j, s := range specs {
+			if widths[j] > 0 {
+				row[j] = s.cell(r, widths[j])
+			}
+		}
+		rows = append(rows, row)
+	}
+	return cols, rows
+}
+
+// nodeTable builds the node table's columns and rows from m.nodeCols.
+func (m *Model) nodeTable() ([]widgets.Column, []widgets.Row) {
+	var specs []nodeColumn
+	var names []string
+	var lays []colLayout
+	var fixed []int
+	for _, c := range m.nodeCols {
+		nc, ok := nodeColumns[c.Name]
+		if !ok {
+			continue
+		}
+		specs = append(specs, nc)
+		names = append(names, c.Name)
+		lays = append(lays, nc.colLayout)
+		fixed = append(fixed, c.Width)
+	}
+	widths := layout(lays, fixed, m.table.Width())
+
+	cols := make([]widgets.Column, len(specs))
+	for i, s := range specs {
+		cols[i] = widgets.Column{Title: s.title, Width: widths[i]}
+	}
+	rows := make([]widgets.Row, 0, len(m.nodes))
+	m.nodeRows = m.nodeRows[:0]
+	for i := range m.nodes {
+		n := &m.nodes[i]
+		row := make(widgets.Row, len(specs))
+		for j, s := range specs {
+			if widths[j] > 0 {
+				row[j] = s.cell(m, n, widths[j])
+			}
+		}
+		rows = append(rows, row)
+		m.nodeRows = append(m.nodeRows, nodeRef{i, -1})
+		if !m.expanded[n.NodeName] {
+			continue
+		}
+		pods := m.nodePods[n.NodeName]
+		for k := range pods {
+			row := make(widgets.Row, len(specs))
+			for j, name := range names {
+				if cell, ok := nodePodCells[name]; ok && widths[j] > 0 {
+					row[j] = cell(m, n, &pods[k], widths[j])
+				}
+				if j == 0 {
+					row[j] = nodePodName(row[j], k == len(pods)-1)
+				}
+			}
+			rows = append(rows, row)
+			m.nodeRows = append(m.nodeRows, nodeRef{i, k})
+		}
+	}
+	return cols, rows
+}
+
+// milli formats a cpu request or limit; 0 means none was set.
+func milli(v int) string {
+	if v == 0 {
+		return "—"
+	}
+	return fmt.Sprintf("%dm", v)
+}
+
+// mebi formats a request or limit; 0 means none was set.
+func mebi(b int64) string {
+	if b == 0 {
+		return "—"
+	}
+	return fmt.Sprintf("%6.1fMi", float64(b)/(1024*1024))
+}
+
+// percent is a as a share of b, "—" without anything to compare with.
+func percent(a, b float64) string {
+	if b == 0 {
+		return "—"
+	}
+	return fmt.Sprintf("%5.1f%%", a/b*100)
+}
+
+// bar and spark are the widgets in the theme's colors.
+func bar(v float64, w int) string { return styles.Bar.Render(widgets.Bar(v, w)) }
+
+func spark(vals []float64, w int) string {
+	if s := widgets.Spark8(vals, w); s != "" {
+		return styles.Spark.Render(s)
+	}
+	return ""
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "—"
+	}
+	return s
+}
+
+// podAge is kubectl's AGE: 45s, 12m, 5h, 3d4h.
+func podAge(created time.Time) string {
+	if created.IsZero() {
+		return "—"
+	}
+	return duration.HumanDuration(time.Since(created))
+}
@@ -0,0 +1,465 @@
+// internal/ui/app/dashboard.go
+package app
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/config"
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/styles"
+)
+
+// dashboard is the full-screen page of several panes at once: the top pods,
+// node utilization, recent warnings and the logs of the pinned pod, laid
+// out in weighted rows (config: dashboard). Pods, nodes and warnings are
+// fetched on open and on every tick while it is shown; the logs pane is the
+// logs stream, sized to its pane by layout.
+type dashboard struct {
+	on  bool
+	cur string // namespace/name of the selected pod, kept across refreshes
+
+	pin domain.LogsTarget // the pod the logs pane follows, Name "" = none
+
+	loaded   bool
+	err      error
+	pods     []domain.PodMetric // by CPU, busiest first
+	nodes    []domain.NodeMetric
+	events   []domain.Event // newest first
+	noEvents bool           // the repo can't list events
+}
+
+type dashboardMsg struct {
+	pods     []domain.PodMetric
+	nodes    []domain.NodeMetric
+	events   []domain.Event
+	noEvents bool
+	err      error
+}
+
+// dashPaneNames are the panes a layout can place.
+var dashPaneNames = []string{"pods", "nodes", "events", "logs"}
+
+// defaultDashboard is the layout unless one is configured.
+var defaultDashboard = config.Dashboard{Rows: []config.DashRow{
+	{Weight: 3, Panes: []config.DashPane{{Pane: "pods", Weight: 2}, {Pane: "nodes"}}},
+	{Weight: 2, Panes: []config.DashPane{{Pane: "events"}, {Pane: "logs", Weight: 2}}},
+}}
+
+func checkDashboard(path string, d config.Dashboard) error {
+	seen := map[string]bool{}
+	for i, r := range d.Rows {
+		for j, p := range r.Panes {
+			if !contains(dashPaneNames, p.Pane) {
+				return fmt.Errorf("%s.rows[%d].panes[%d]: unknown pane %q (have: %s)", path, i, j, p.Pane, strings.Join(dashPaneNames, ", "))
+			}
+			if seen[p.Pane] {
+				return fmt.Errorf("%s.rows[%d].panes[%d]: pane %s is already placed", path, i, j, p.Pane)
+			}
+			seen[p.Pane] = true
+		}
+	}
+	return nil
+}
+
+// openDashboard shows the dashboard. The table's logs pane is closed; the
+// pinned pod's logs stream instead.
+func (m *Model) openDashboard() tea.Cmd {
+	m.dash.on, m.dash.loaded = true, false
+	m.closeLogs()
+	if m.dash.pin.Name == "" {
+		return tea.Batch(m.fetchDashboard(), m.relayout())
+	}
+	m.logPrevious = false
+	return tea.Batch(m.fetchDashboard(), m.openLogs(m.dash.pin))
+}
+
+func (m *Model) closeDashboard() tea.Cmd {
+	m.dash.on = false
+	m.closeLogs()
+	return m.relayout()
+}
+
+func (m Model) fetchDashboard() tea.Cmd {
+	return func() tea.Msg {
+		var msg dashboardMsg
+		msg.pods, msg.err = m.repoM.ListPods(m.ctx, m.ns, m.selector)
+		if msg.err != nil {
+			return msg
+		}
+		if msg.nodes, msg.err = m.repoM.ListNodes(m.ctx); msg.err != nil {
+			return msg
+		}
+		w, ok := m.repoM.(domain.Warnings)
+		if !ok {
+			msg.noEvents = true
+			return msg
+		}
+		msg.events, msg.err = w.ListWarnings(m.ctx, m.ns)
+		return msg
+	}
+}
+
+func (m *Model) setDashboard(msg dashboardMsg) {
+	d := &m.dash
+	if !d.on {
+		return
+	}
+	d.loaded, d.err = true, msg.err
+	if msg.err != nil {
+		return
+	}
+	sort.SliceStable(msg.pods, func(i, j int) bool {
+		if msg.pods[i].CPUm != msg.pods[j].CPUm {
+			return msg.pods[i].CPUm > msg.pods[j].CPUm
+		}
+		return msg.pods[i].PodName < msg.pods[j].PodName
+	})
+	sort.SliceStable(msg.nodes, func(i, j int) bool { return msg.nodes[i].CPUUsed > msg.nodes[j].CPUUsed })
+	d.pods, d.nodes, d.events, d.noEvents = msg.pods, msg.nodes, msg.events, msg.noEvents
+}
+
+func podKey(p domain.PodMetric) string { return p.Namespace + "/" + p.PodName }
+
+// dashRow is the index of the selected pod, the first when it is gone.
+func (d dashboard) dashRow() int {
+	for i, p := range d.pods {
+		if podKey(p) == d.cur {
+			return i
+		}
+	}
+	return 0
+}
+
+func (d dashboard) selected() (domain.PodMetric, bool) {
+	if len(d.pods) == 0 {
+		return domain.PodMetric{}, false
+	}
+	return d.pods[d.dashRow()], true
+}
+
+func (d *dashboard) moveTo(i int) {
+	if len(d.pods) > 0 {
+		d.cur = podKey(d.pods[clamp(i, 0, len(d.pods)-1)])
+	}
+}
+
+// pinTarget is the logs target of a pinned pod.
+func pinTarget(p domain.PodMetric) domain.LogsTarget {
+	return domain.LogsTarget{Namespace: p.Namespace, Kind: "Pod", Name: p.PodName, Container: p.Container}
+}
+
+// pin makes t the pod the dashboard's logs pane follows; outside the
+// dashboard it is only remembered.
+func (m *Model) pin(t domain.LogsTarget) tea.Cmd {
+	if t.Kind != "Pod" || t.Name == "" {
+		return nil
+	}
+	m.dash.pin = t
+	if !m.dash.on {
+		m.status = fmt.Sprintf("pinned %s/%s for the dashboard (%s)", t.Namespace, t.Name, m.keys.Dashboard.Help().Key)
+		return nil
+	}
+	m.logPrevious = false
+	return m.openLogs(t)
+}
+
+// updateDashboard handles keys while the dashboard is shown: up and down
+// pick a pod, the page motions scroll the logs.
+func (m Model) updateDashboard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	k := m.keys
+	d := &m.dash
+	switch {
+	case key.Matches(msg, k.Back):
+		return m, m.closeDashboard()
+	case key.Matches(msg, k.Quit):
+		m.closeDashboard()
+		return m.Update(msg)
+	case key.Matches(msg, k.Up):
+		n, _ := m.popCount()
+		d.moveTo(d.dashRow() - n)
+	case key.Matches(msg, k.Down):
+		n, _ := m.popCount()
+		d.moveTo(d.dashRow() + n)
+	case key.Matches(msg, k.Pin):
+		if p, ok := d.selected(); ok {
+			return m, m.pin(pinTarget(p))
+		}
+	case key.Matches(msg, k.Detail):
+		if p, ok := d.selected(); ok {
+			return m, m.showDetail(detail{kind: ViewPods, ns: p.Namespace, name: p.PodName}, "")
+		}
+	default:
+		m.moveLogs(msg)
+	}
+	return m, nil
+}
+
+// dashRect is a pane's box on the page, border included.
+type dashRect struct {
+	pane       string
+	x, y, w, h int
+}
+
+// weigh splits n cells by weight, 1 where none is set; what rounding
+// leaves over goes to the first parts.
+func weigh(n int, weights []int) []int {
+	if len(weights) == 0 {
+		return nil
+	}
+	total := 0
+	for i, w := range weights {
+		if w <= 0 {
+			weights[i] = 1
+		}
+		total += weights[i]
+	}
+	out := make([]int, len(weights))
+	left := n
+	for i, w := range weights {
+		out[i] = n * w / total
+		left -= out[i]
+	}
+	for i := 0; left > 0; i = (i + 1) % len(out) {
+		out[i]++
+		left--
+	}
+	return out
+}
+
+// dashHeight is what the panes get: all but the header and the footer.
+func (m Model) dashHeight() int { return max(3, m.height-2) }
+
+// dashPanes lays the configured rows out over the page, row by row.
+func (m Model) dashPanes() [][]dashRect {
+	rows := m.dashLayout.Rows
+	ws := make([]int, len(rows))
+	for i, r := range rows {
+		ws[i] = r.Weight
+	}
+	out := make([][]dashRect, len(rows))
+	y := 0
+	for i, h := range weigh(m.dashHeight(), ws) {
+		pw := make([]int, len(rows[i].Panes))
+		for j, p := range rows[i].Panes {
+			pw[j] = p.Weight
+		}
+		x := 0
+		for j, w := range weigh(m.width, pw) {
+			out[i] = append(out[i], dashRect{rows[i].Panes[j].Pane, x, y, w, h})
+			x += w
+		}
+		y += h
+	}
+	return out
+}
+
+// dashPane finds the pane named name.
+func (m Model) dashPane(name string) (dashRect, bool) {
+	for _, row := range m.dashPanes() {
+		for _, r := range row {
+			if r.pane == name {
+				return r, true
+			}
+		}
+	}
+	return dashRect{}, false
+}
+
+// inner is the text area of a pane: inside the border and padding.
+func (r dashRect) inner() (w, h int) { return max(1, r.w-4), max(1, r.h-2) }
+
+// sizeDashLogs fits the logs viewport to the logs pane, under its title.
+func (m *Model) sizeDashLogs() {
+	if r, ok := m.dashPane("logs"); ok {
+		w, h := r.inner()
+		m.logsVP.Width, m.logsVP.Height = w, max(1, h-1)
+	}
+}
+
+// dashboardView is the page: the panes, row by row.
+func (m Model) dashboardView() string {
+	var rows []string
+	for _, row := range m.dashPanes() {
+		var boxes []string
+		for _, r := range row {
+			if r.w >= 4 && r.h >= 3 {
+				boxes = append(boxes, m.dashBox(r))
+			}
+		}
+		if len(boxes) > 0 {
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, boxes...))
+		}
+	}
+	return lipgloss.NewStyle().Height(m.dashHeight()).MaxHeight(m.dashHeight()).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
+func (m Model) dashBox(r dashRect) string {
+	w, h := r.inner()
+	var lines []string
+	switch {
+	case r.pane == "logs":
+		lines = m.dashLogs()
+	case m.dash.err != nil:
+		lines = []string{styles.Title.Render(r.pane), styles.Danger.Render(m.dash.err.Error())}
+	case !m.dash.loaded:
+		lines = []string{styles.Title.Render(r.pane), styles.Faint.Render("loading…")}
+	case r.pane == "pods":
+		lines = m.dashPods(w, h)
+	case r.pane == "nodes":
+		lines = m.dashNodes(w)
+	case r.pane == "events":
+		lines = m.dashEvents(w)
+	}
+	lines = lines[:min(len(lines), h)]
+	for i, l := range lines {
+		lines[i] = ansi.Truncate(l, w, "…")
+	}
+	return styles.Box.Width(r.w - 2).Height(h).MaxHeight(r.h).Render(strings.Join(lines, "\n"))
+}
+
+// dashTop is the first pod shown in a pods pane of h lines, so the
+// selection stays in view.
+func (d dashboard) dashTop(h int) int {
+	return max(0, d.dashRow()-(h-2)+1) // title and column header
+}
+
+// dashPods lists pods by CPU: usage and a bar against the request, colored
+// by the worst threshold as in the table. The pinned pod is in the accent.
+func (m Model) dashPods(w, h int) []string {
+	d := m.dash
+	lines := []string{styles.Title.Render("Top pods by CPU") + styles.Faint.Render("   ns: "+m.ns+"   "+plural(len(d.pods), "pod"))}
+	if len(d.pods) == 0 {
+		return append(lines, styles.Faint.Render("no pods"))
+	}
+	bw := clamp((w-18)/4, 4, 16) // cursor, two amounts and the spaces take 18
+	nw := max(8, w-18-2*bw)
+	lines = append(lines, styles.Faint.Render(fmt.Sprintf("  %-*s %6s %-*s %6s", nw, "POD", "CPU", bw, "", "MEM")))
+	sel := d.dashRow()
+	for i := d.dashTop(h); i < len(d.pods) && len(lines) < h; i++ {
+		p := d.pods[i]
+		cpu, mem := m.podCPU(&p), m.podMem(&p)
+		name := fmt.Sprintf("%-*s", nw, truncate(p.PodName, nw))
+		switch {
+		case p.Namespace == d.pin.Namespace && p.PodName == d.pin.Name:
+			name = styles.TabActive.Render(name)
+		case p.Phase != "Succeeded" && (p.Phase != "Running" || readyRatio(p.Ready) < 1):
+			name = styles.Danger.Render(name)
+		}
+		cursor := "  "
+		if i == sel {
+			cursor = styles.TabActive.Render("▶ ")
+		}
+		lines = append(lines, fmt.Sprintf("%s%s %6s %s %6s %s", cursor, name,
+			cpu.paint(cores(float64(p.CPUm))), cpu.bar(math.Min(ratio(float64(p.CPUm), float64(p.CPUReqm)), 1), bw),
+			mem.paint(bytesIEC(float64(p.MemBytes))), mem.bar(math.Min(ratio(float64(p.MemBytes), float64(p.MemReqBytes)), 1), bw)))
+	}
+	return lines
+}
+
+// dashNodes is the cluster's average use and every node's, busiest first.
+func (m Model) dashNodes(w int) []string {
+	d := m.dash
+	var cpu, mem float64
+	for _, n := range d.nodes {
+		cpu += n.CPUUsed
+		mem += n.MEMUsed
+	}
+	count := float64(max(1, len(d.nodes)))
+	lines := []string{styles.Title.Render("Nodes") + styles.Faint.Render(fmt.Sprintf("   %s   avg CPU %s  MEM %s", plural(len(d.nodes), "node"), share(cpu/count), share(mem/count)))}
+	nw := 4
+	for _, n := range d.nodes {
+		nw = max(nw, len(n.NodeName))
+	}
+	nw = min(nw, max(8, w/3))
+	bw := clamp((w-nw-12)/2, 3, 20)
+	lines = append(lines, styles.Faint.Render(fmt.Sprintf("%-*s %4s %-*s %4s", nw, "NODE", "CPU", bw, "", "MEM")))
+	for _, n := range d.nodes {
+		c, mm := m.severity("node.cpu", n.CPUUsed, 1), m.severity("node.mem", n.MEMUsed, 1)
+		name := fmt.Sprintf("%-*s", nw, truncate(n.NodeName, nw))
+		switch {
+		case n.NotReady:
+			name = styles.Danger.Render(name)
+		case len(n.Pressure) > 0:
+			name = styles.Warn.Render(name)
+		}
+		lines = append(lines, fmt.Sprintf("%s %4s %s %4s %s", name,
+			c.paint(share(n.CPUUsed)), c.bar(math.Min(n.CPUUsed, 1), bw),
+			mm.paint(share(n.MEMUsed)), mm.bar(math.Min(n.MEMUsed, 1), bw)))
+	}
+	return lines
+}
+
+// dashEvents lists the warnings, newest first.
+func (m Model) dashEvents(w int) []string {
+	d := m.dash
+	lines := []string{styles.Title.Render("Warnings") + styles.Faint.Render("   "+plural(len(d.events), "event"))}
+	switch {
+	case d.noEvents:
+		return append(lines, styles.Faint.Render("events can't be listed here"))
+	case len(d.events) == 0:
+		return append(lines, styles.Faint.Render("no recent warnings"))
+	}
+	for _, e := range d.events {
+		obj := e.Object
+		if m.ns == "all" && e.Namespace != "" {
+			obj = e.Namespace + "/" + obj
+		}
+		count := ""
+		if e.Count > 1 {
+			count = fmt.Sprintf(" ×%d", e.Count)
+		}
+		lines = append(lines, fmt.Sprintf("%4s %s %s%s %s", podAge(e.Last), styles.Warn.Render(e.Reason),
+			obj, styles.Faint.Render(count), styles.Faint.Render(e.Message)))
+	}
+	return lines
+}
+
+// dashLogs is the logs title and stream of the pinned pod.
+func (m Model) dashLogs() []string {
+	if m.dash.pin.Name == "" {
+		return []string{styles.Title.Render("Logs"), styles.Faint.Render(fmt.Sprintf("no pod pinned: pick one under Top pods and press %s", m.keys.Pin.Help().Key))}
+	}
+	return append([]string{m.logsTitle()}, strings.Split(m.logsVP.View(), "\n")...)
+}
+
+// dashMouse: the wheel scrolls the pane it is over; a click picks a pod,
+// and a click on the picked pod pins it.
+func (m *Model) dashMouse(msg tea.MouseMsg, wheel int) tea.Cmd {
+	x, y := msg.X, msg.Y-lipgloss.Height(styles.Header.Render("x"))
+	d := &m.dash
+	for _, row := range m.dashPanes() {
+		for _, r := range row {
+			if x < r.x || x >= r.x+r.w || y < r.y || y >= r.y+r.h {
+				continue
+			}
+			switch {
+			case r.pane == "logs" && wheel < 0:
+				m.scrollLogs(-wheelLines)
+			case r.pane == "logs" && wheel > 0:
+				m.scrollLogs(wheelLines)
+			case r.pane == "pods" && wheel != 0:
+				d.moveTo(d.dashRow() + wheel)
+			case r.pane == "pods":
+				_, h := r.inner()
+				i := d.dashTop(h) + y - r.y - 3 // border, title and column header
+				if i < d.dashTop(h) || i >= len(d.pods) {
+					return nil
+				}
+				if i == d.dashRow() {
+					return m.pin(pinTarget(d.pods[i]))
+				}
+				d.moveTo(i)
+			}
+			return nil
+		}
+	}
+	return nil
+}
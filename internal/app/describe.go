@@ -0,0 +1,259 @@
+// internal/ui/app/describe.go
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/config"
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/styles"
+)
+
+// The detail page's YAML and Describe tabs: the object as `kubectl get -o
+// yaml` and `kubectl describe` would print it.
+
+// hideNoise drops metadata.managedFields and status from a manifest as the
+// API server's YAML lays them out (lists at their key's indent).
+func hideNoise(doc string) string {
+	lines := strings.Split(doc, "\n")
+	out := lines[:0:0]
+	skip := -1 // indent of the block being dropped
+	for _, l := range lines {
+		indent := len(l) - len(strings.TrimLeft(l, " "))
+		if skip >= 0 {
+			if l == "" || indent > skip || (indent == skip && strings.HasPrefix(l[indent:], "- ")) {
+				continue
+			}
+			skip = -1
+		}
+		if l == "status:" || l == "  managedFields:" {
+			skip = indent
+			continue
+		}
+		out = append(out, l)
+	}
+	return strings.Join(out, "\n")
+}
+
+var (
+	yamlKey    = regexp.MustCompile(`^(\s*(?:- )?)([^\s:#"'][^:#]*|"[^"]*"|'[^']*'):(\s+|$)(.*)$`)
+	yamlItem   = regexp.MustCompile(`^(\s*)- (.*)$`)
+	yamlScalar = regexp.MustCompile(`^(-?\d+(\.\d+)?|true|false|null|~|\{\}|\[\])$`)
+)
+
+// highlightYAML colors one line: keys in the accent color, numbers,
+// booleans and null apart from strings, comments faint.
+func highlightYAML(l string) string {
+	if strings.HasPrefix(strings.TrimSpace(l), "#") {
+		return styles.Faint.Render(l)
+	}
+	if m := yamlKey.FindStringSubmatch(l); m != nil {
+		return m[1] + styles.TabActive.Render(m[2]) + ":" + m[3] + yamlValue(m[4])
+	}
+	if m := yamlItem.FindStringSubmatch(l); m != nil {
+		return m[1] + "- " + yamlValue(m[2])
+	}
+	return l
+}
+
+func yamlValue(v string) string {
+	switch {
+	case v == "":
+		return ""
+	case yamlScalar.MatchString(v):
+		return styles.Spark.Render(v)
+	case strings.HasPrefix(v, `"`) || strings.HasPrefix(v, `'`):
+		return styles.Good.Render(v)
+	}
+	return v
+}
+
+// describer writes kubectl describe's "Key:  value" layout, with nested
+// sections indented two spaces per level.
+type describer struct {
+	b strings.Builder
+	w *tabwriter.Writer
+}
+
+func newDescriber() *describer {
+	d := &describer{}
+	d.w = tabwriter.NewWriter(&d.b, 0, 8, 2, ' ', 0)
+	return d
+}
+
+func (d *describer) line(level int, format string, args ...any) {
+	fmt.Fprintf(d.w, strings.Repeat("  ", level)+format+"\n", args...)
+}
+
+// list writes a multi-value field: the first value after the key, the rest
+// lined up under it.
+func (d *describer) list(level int, key string, vals []string) {
+	if len(vals) == 0 {
+		d.line(level, "%s:\t<none>", key)
+		return
+	}
+	d.line(level, "%s:\t%s", key, vals[0])
+	for _, v := range vals[1:] {
+		d.line(level, "\t%s", v)
+	}
+}
+
+func (d *describer) events(es []domain.Event) {
+	d.w.Flush() // a table of its own, not aligned with the fields above
+	if len(es) == 0 {
+		d.line(0, "Events:\t<none>")
+		return
+	}
+	d.line(0, "Events:")
+	d.line(1, "Type\tReason\tAge\tMessage")
+	d.line(1, "----\t------\t---\t-------")
+	for _, e := range es {
+		age := podAge(e.Last)
+		if e.Count > 1 {
+			age = fmt.Sprintf("%s (x%d)", age, e.Count)
+		}
+		d.line(1, "%s\t%s\t%s\t%s", e.Type, e.Reason, age, e.Message)
+	}
+}
+
+func (d *describer) String() string {
+	d.w.Flush()
+	return d.b.String()
+}
+
+func describePod(p domain.PodMetric, d domain.PodDetail) string {
+	w := newDescriber()
+	w.line(0, "Name:\t%s", p.PodName)
+	w.line(0, "Namespace:\t%s", p.Namespace)
+	w.line(0, "Service Account:\t%s", dash(d.ServiceAccount))
+	w.line(0, "Node:\t%s", dash(p.NodeName))
+	if !d.Started.IsZero() {
+		w.line(0, "Start Time:\t%s", d.Started.Format("Mon, 02 Jan 2006 15:04:05 -0700"))
+	}
+	w.list(0, "Labels", labelPairs(p.Labels))
+	w.line(0, "Status:\t%s", dash(p.Phase))
+	w.line(0, "IP:\t%s", dash(d.IP))
+	if p.OwnerKind != "" {
+		w.line(0, "Controlled By:\t%s/%s", p.OwnerKind, p.OwnerName)
+	}
+	var init, ctrs []domain.ContainerDetail
+	for _, c := range d.Containers {
+		if c.Init {
+			init = append(init, c)
+		} else {
+			ctrs = append(ctrs, c)
+		}
+	}
+	for _, sec := range []struct {
+		title string
+		cs    []domain.ContainerDetail
+	}{{"Init Containers", init}, {"Containers", ctrs}} {
+		if len(sec.cs) == 0 {
+			continue
+		}
+		w.line(0, "%s:", sec.title)
+		for _, c := range sec.cs {
+			w.line(1, "%s:", c.Name)
+			w.line(2, "Image:\t%s", c.Image)
+			w.line(2, "State:\t%s", c.State)
+			if c.LastExit != "" {
+				w.line(2, "Last State:\tTerminated: %s", c.LastExit)
+			}
+			w.line(2, "Ready:\t%t", c.Ready)
+			w.line(2, "Restart Count:\t%d", c.Restarts)
+			resources(w, "Limits", c.CPULimm, c.MemLimBytes)
+			resources(w, "Requests", c.CPUReqm, c.MemReqBytes)
+			for _, pr := range [][2]string{{"Liveness", c.Liveness}, {"Readiness", c.Readiness}, {"Startup", c.Startup}} {
+				if pr[1] != "" {
+					w.line(2, "%s:\t%s", pr[0], pr[1])
+				}
+			}
+		}
+	}
+	if len(d.Conditions) > 0 {
+		w.line(0, "Conditions:")
+		w.line(1, "Type\tStatus")
+		for _, c := range d.Conditions {
+			w.line(1, "%s\t%s", c.Type, c.Status)
+		}
+	}
+	w.line(0, "QoS Class:\t%s", dash(config.Or(d.QoS, p.QoS)))
+	w.events(d.Events)
+	return w.String()
+}
+
+func resources(w *describer, title string, cpuMil int, memBytes int64) {
+	if cpuMil == 0 && memBytes == 0 {
+		return
+	}
+	w.line(2, "%s:", title)
+	if cpuMil > 0 {
+		w.line(3, "cpu:\t%s", cores(float64(cpuMil)))
+	}
+	if memBytes > 0 {
+		w.line(3, "memory:\t%s", bytesIEC(float64(memBytes)))
+	}
+}
+
+func describeNode(n domain.NodeMetric, d domain.NodeDetail, pods []domain.PodMetric) string {
+	w := newDescriber()
+	w.line(0, "Name:\t%s", n.NodeName)
+	w.list(0, "Labels", labelPairs(d.Labels))
+	if !d.Created.IsZero() {
+		w.line(0, "CreationTimestamp:\t%s", d.Created.Format("Mon, 02 Jan 2006 15:04:05 -0700"))
+	}
+	w.list(0, "Taints", d.Taints)
+	w.line(0, "Unschedulable:\t%t", d.Unschedulable)
+	if len(d.Conditions) > 0 {
+		w.line(0, "Conditions:")
+		w.line(1, "Type\tStatus\tLastTransitionTime\tReason\tMessage")
+		w.line(1, "----\t------\t------------------\t------\t-------")
+		for _, c := range d.Conditions {
+			w.line(1, "%s\t%s\t%s\t%s\t%s", c.Type, c.Status, c.Since.Format("Mon, 02 Jan 2006 15:04:05 -0700"), c.Reason, c.Message)
+		}
+	}
+	w.list(0, "Addresses", d.Addresses)
+	for _, r := range []struct {
+		title string
+		v     domain.Resources
+	}{{"Capacity", d.Capacity}, {"Allocatable", d.Allocatable}} {
+		w.line(0, "%s:", r.title)
+		w.line(1, "cpu:\t%s", num(r.v.CPUm, cores))
+		w.line(1, "ephemeral-storage:\t%s", num(r.v.DiskBytes, bytesIEC))
+		w.line(1, "memory:\t%s", num(r.v.MemBytes, bytesIEC))
+		w.line(1, "pods:\t%d", r.v.Pods)
+	}
+	w.line(0, "System Info:")
+	w.line(1, "Kernel Version:\t%s", dash(d.Kernel))
+	w.line(1, "OS Image:\t%s", dash(d.OS))
+	w.line(1, "Container Runtime Version:\t%s", dash(d.Runtime))
+	w.line(1, "Kubelet Version:\t%s", dash(config.Or(d.Kubelet, n.K8sVer)))
+	w.w.Flush()
+	w.line(0, "Non-terminated Pods:\t(%d in total)", len(pods))
+	if len(pods) > 0 {
+		w.line(1, "Namespace\tName\tCPU Requests\tCPU Limits\tMemory Requests\tMemory Limits\tAge")
+		w.line(1, "---------\t----\t------------\t----------\t---------------\t-------------\t---")
+		for _, p := range pods {
+			w.line(1, "%s\t%s\t%s\t%s\t%s\t%s\t%s", p.Namespace, p.PodName,
+				num(p.CPUReqm, cores), num(p.CPULimm, cores), num(p.MemReqBytes, bytesIEC), num(p.MemLimBytes, bytesIEC), podAge(p.Created))
+		}
+	}
+	w.events(d.Events)
+	return w.String()
+}
+
+// highlightDescribe makes the top-level keys stand out and warnings warn.
+func highlightDescribe(l string) string {
+	switch {
+	case strings.Contains(l, "  Warning  "):
+		return styles.Warn.Render(l)
+	case l != "" && l[0] != ' ':
+		if k, rest, ok := strings.Cut(l, ":"); ok {
+			return styles.Title.Render(k+":") + rest
+		}
+	}
+	return l
+}
@@ -0,0 +1,665 @@
+// internal/ui/app/detail.go
+package app
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/config"
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/styles"
+)
+
+// detail is the full-screen page of one pod or node, split into tabs. The
+// usage numbers and charts come from the regular refresh; the rest is
+// fetched through domain.Describer on open and on every tick, and the
+// manifest through domain.Manifests while the YAML tab is shown.
+type detail struct {
+	kind     View
+	ns, name string
+	tab      int
+	vp       viewport.Model
+
+	loaded      bool
+	err         error
+	pod         domain.PodDetail
+	node        domain.NodeDetail
+	pods        []domain.PodMetric // node: the pods scheduled on it
+	manifest    []byte
+	manifestErr error
+	trim        bool // YAML without managedFields and status
+
+	search     *regexp.Regexp
+	searchText string
+	matches    []int // lines of the body with a search hit
+	matchIdx   int
+}
+
+type detailMsg struct {
+	kind        View
+	ns, name    string
+	pod         domain.PodDetail
+	node        domain.NodeDetail
+	pods        []domain.PodMetric
+	err         error
+	manifest    []byte
+	manifestErr error
+}
+
+var (
+	podTabs  = []string{"Overview", "Containers", "Events", "Metrics", "Describe", "YAML"}
+	nodeTabs = []string{"Overview", "Pods", "Labels", "Events", "Metrics", "Describe", "YAML"}
+)
+
+func (d detail) open() bool { return d.name != "" }
+
+func (d detail) title() string {
+	if d.kind == ViewNodes {
+		return "Node " + d.name
+	}
+	return "Pod " + d.ns + "/" + d.name
+}
+
+// tabAt is the tab under column x of the tab bar, -1 for none.
+func (d detail) tabAt(x int) int {
+	at := 1 + ansi.StringWidth(d.title()) + 3 // page padding, the title and its gap
+	for i, t := range d.tabs() {
+		if x >= at && x < at+ansi.StringWidth(t) {
+			return i
+		}
+		at += ansi.StringWidth(t) + 2
+	}
+	return -1
+}
+
+func (d detail) tabs() []string {
+	if d.kind == ViewNodes {
+		return nodeTabs
+	}
+	return podTabs
+}
+
+// openDetail opens the page of the selected row at the named tab.
+func (m *Model) openDetail(tab string) tea.Cmd {
+	d := detail{kind: m.view}
+	switch {
+	case m.view == ViewPods && len(m.pods) > 0:
+		p := m.pods[m.currentSelection()%len(m.pods)]
+		d.ns, d.name = p.Namespace, p.PodName
+	case m.view == ViewNodes && len(m.nodeRows) > 0:
+		if p, ok := m.selectedNodePod(); ok {
+			d.kind, d.ns, d.name = ViewPods, p.Namespace, p.PodName
+			break
+		}
+		n, _ := m.selectedNode()
+		d.name = n.NodeName
+	default:
+		return nil
+	}
+	return m.showDetail(d, tab)
+}
+
+// showDetail opens the page d names at the named tab.
+func (m *Model) showDetail(d detail, tab string) tea.Cmd {
+	d.vp = viewport.New(m.width-2, m.detailHeight())
+	d.tab = max(0, indexOf(d.tabs(), tab))
+	m.detail = d
+	m.renderDetail()
+	return m.fetchDetail()
+}
+
+// detailHeight is what the page body gets: all but the header, the tab
+// bar and the footer.
+func (m Model) detailHeight() int { return max(5, m.height-3) }
+
+func (m Model) fetchDetail() tea.Cmd {
+	d := m.detail
+	yaml := d.tabs()[d.tab] == "YAML"
+	return func() tea.Msg {
+		msg := detailMsg{kind: d.kind, ns: d.ns, name: d.name}
+		if d.kind == ViewNodes {
+			msg.pods, _ = m.repoM.ListPods(m.ctx, "all", domain.Selector{Fields: "spec.nodeName=" + d.name})
+		}
+		if yaml {
+			if mf, ok := m.repoM.(domain.Manifests); ok {
+				kind := "Pod"
+				if d.kind == ViewNodes {
+					kind = "Node"
+				}
+				msg.manifest, msg.manifestErr = mf.Manifest(m.ctx, kind, d.ns, d.name)
+			} else {
+				msg.manifestErr = errors.New("this metrics source can't fetch manifests")
+			}
+		}
+		desc, ok := m.repoM.(domain.Describer)
+		if !ok {
+			msg.err = errors.New("this metrics source can't describe objects; showing metrics only")
+			return msg
+		}
+		if d.kind == ViewNodes {
+			msg.node, msg.err = desc.DescribeNode(m.ctx, d.name)
+		} else {
+			msg.pod, msg.err = desc.DescribePod(m.ctx, d.ns, d.name)
+		}
+		return msg
+	}
+}
+
+// setDetail takes a fetch in, unless the page was closed or moved on.
+func (m *Model) setDetail(msg detailMsg) {
+	d := &m.detail
+	if msg.kind != d.kind || msg.ns != d.ns || msg.name != d.name {
+		return
+	}
+	d.loaded, d.err = true, msg.err
+	if msg.err == nil {
+		d.pod, d.node = msg.pod, msg.node
+	}
+	sort.SliceStable(msg.pods, func(i, j int) bool { return msg.pods[i].CPUm > msg.pods[j].CPUm })
+	d.pods = msg.pods
+	if msg.manifest != nil || msg.manifestErr != nil {
+		d.manifest, d.manifestErr = msg.manifest, msg.manifestErr
+	}
+	m.renderDetail()
+}
+
+// gotoTab shows tab i, fetching the manifest the first time YAML is shown.
+func (m *Model) gotoTab(i int) tea.Cmd {
+	d := &m.detail
+	d.tab = i
+	d.vp.GotoTop()
+	d.matchIdx = 0
+	m.renderDetail()
+	if d.tabs()[i] == "YAML" && d.manifest == nil {
+		return m.fetchDetail()
+	}
+	return nil
+}
+
+// jumpToDetailMatch scrolls to the i-th search hit, wrapping around.
+func (m *Model) jumpToDetailMatch(i int) {
+	d := &m.detail
+	if len(d.matches) == 0 {
+		d.matchIdx = 0
+		return
+	}
+	n := len(d.matches)
+	d.matchIdx = ((i % n) + n) % n
+	m.renderDetail()
+	d.vp.SetYOffset(d.matches[d.matchIdx] - d.vp.Height/2)
+}
+
+// updateDetail handles keys while the detail page is open.
+func (m Model) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	k := m.keys
+	d := &m.detail
+	switch {
+	case key.Matches(msg, k.Back):
+		m.detail = detail{}
+		return m, nil
+	case key.Matches(msg, k.Quit):
+		m.detail = detail{}
+		return m.Update(msg)
+	case key.Matches(msg, k.NextTab):
+		return m, m.gotoTab((d.tab + 1) % len(d.tabs()))
+	case key.Matches(msg, k.PrevTab):
+		return m, m.gotoTab((d.tab + len(d.tabs()) - 1) % len(d.tabs()))
+	case key.Matches(msg, k.Manifest):
+		return m, m.gotoTab(indexOf(d.tabs(), "YAML"))
+	case key.Matches(msg, k.Describe):
+		return m, m.gotoTab(indexOf(d.tabs(), "Describe"))
+	case key.Matches(msg, k.HideFields):
+		d.trim = !d.trim
+		m.renderDetail()
+		return m, nil
+	case key.Matches(msg, k.LogSearch):
+		return m, m.openPrompt(promptDetailSearch, "/", d.searchText)
+	case key.Matches(msg, k.LogNext):
+		m.jumpToDetailMatch(d.matchIdx + 1)
+		return m, nil
+	case key.Matches(msg, k.LogPrev):
+		m.jumpToDetailMatch(d.matchIdx - 1)
+		return m, nil
+	}
+	m.moveViewport(&d.vp, msg)
+	return m, nil
+}
+
+// renderDetail refills the page for the current tab and data.
+func (m *Model) renderDetail() {
+	d := &m.detail
+	if !d.open() {
+		return
+	}
+	d.vp.Width, d.vp.Height = m.width-2, m.detailHeight()
+	var body string
+	tab, err, loaded := d.tabs()[d.tab], d.err, d.loaded
+	switch {
+	case tab == "Metrics":
+		body = m.detailCharts()
+	case tab == "YAML":
+		body = m.manifestView()
+		err, loaded = d.manifestErr, d.manifest != nil
+	case tab == "Describe" && d.kind == ViewNodes:
+		body = colorLines(describeNode(m.detailNode(), d.node, d.pods), highlightDescribe)
+	case tab == "Describe":
+		body = colorLines(describePod(m.detailPod(), d.pod), highlightDescribe)
+	case d.kind == ViewNodes:
+		body = m.nodeDetailTab(tab)
+	default:
+		body = m.podDetailTab(tab)
+	}
+	switch {
+	case err != nil:
+		body = styles.Danger.Render(err.Error()) + "\n\n" + body
+	case !loaded:
+		body = styles.Faint.Render("loading…") + "\n\n" + body
+	}
+	lines := strings.Split(body, "\n")
+	d.matches = d.matches[:0]
+	for i, l := range lines {
+		// hits are highlighted on the plain text, so they never split an
+		// escape sequence; the line loses its other colors
+		if plain := ansi.Strip(l); d.search != nil && d.search.MatchString(plain) {
+			st := styles.Match
+			if len(d.matches) == d.matchIdx {
+				st = styles.MatchCurrent
+			}
+			d.matches = append(d.matches, i)
+			l = d.search.ReplaceAllStringFunc(plain, func(x string) string { return st.Render(x) })
+		}
+		lines[i] = ansi.Truncate(l, d.vp.Width, "…")
+	}
+	d.vp.SetContent(strings.Join(lines, "\n"))
+}
+
+// detailView is the page: a title with the tabs, then the body.
+func (m Model) detailView() string {
+	d := m.detail
+	title := d.title()
+	tabs := make([]string, len(d.tabs()))
+	for i, t := range d.tabs() {
+		if i == d.tab {
+			tabs[i] = styles.TabActive.Underline(true).Render(t)
+		} else {
+			tabs[i] = styles.Tab.Render(t)
+		}
+	}
+	bar := styles.Title.Render(title) + "   " + strings.Join(tabs, "  ")
+	if d.search != nil {
+		bar += styles.Faint.Render(fmt.Sprintf("   /%s %d/%d", d.searchText, min(d.matchIdx+1, len(d.matches)), len(d.matches)))
+	}
+	bar = ansi.Truncate(bar, m.width-2, "…")
+	return lipgloss.NewStyle().Padding(0, 1).Render(bar + "\n" + d.vp.View())
+}
+
+// detailPod and detailNode are the page's object as of the last refresh.
+func (m Model) detailPod() domain.PodMetric {
+	for _, p := range m.allPods {
+		if p.Namespace == m.detail.ns && p.PodName == m.detail.name {
+			return p
+		}
+	}
+	for _, ps := range m.nodePods {
+		for _, p := range ps {
+			if p.Namespace == m.detail.ns && p.PodName == m.detail.name {
+				return p
+			}
+		}
+	}
+	return domain.PodMetric{Namespace: m.detail.ns, PodName: m.detail.name}
+}
+
+func (m Model) detailNode() domain.NodeMetric {
+	for _, n := range m.allNodes {
+		if n.NodeName == m.detail.name {
+			return n
+		}
+	}
+	return domain.NodeMetric{NodeName: m.detail.name}
+}
+
+// manifestView is the YAML tab: the object as the API server returns it,
+// highlighted, with managedFields and status dropped when trimmed.
+func (m Model) manifestView() string {
+	d := m.detail
+	if d.manifest == nil {
+		return ""
+	}
+	doc, note := strings.TrimSuffix(string(d.manifest), "\n"), "m: hide managedFields and status"
+	if d.trim {
+		doc, note = hideNoise(doc), "managedFields and status hidden (m: show)"
+	}
+	return styles.Faint.Render("# "+note) + "\n" + colorLines(doc, highlightYAML)
+}
+
+// colorLines colors a document line by line.
+func colorLines(doc string, line func(string) string) string {
+	lines := strings.Split(doc, "\n")
+	for i, l := range lines {
+		lines[i] = line(l)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) podDetailTab(tab string) string {
+	d, p := m.detail.pod, m.detailPod()
+	switch tab {
+	case "Containers":
+		var blocks []string
+		for _, c := range d.Containers {
+			blocks = append(blocks, containerBlock(c))
+		}
+		if len(blocks) == 0 {
+			return styles.Faint.Render("no containers")
+		}
+		return strings.Join(blocks, "\n\n")
+	case "Events":
+		return eventsTable(d.Events)
+	}
+
+	owner := "—"
+	if p.OwnerKind != "" {
+		owner = p.OwnerKind + "/" + p.OwnerName
+	}
+	started := "—"
+	if !d.Started.IsZero() {
+		started = podAge(d.Started) + " ago  " + styles.Faint.Render(d.Started.Format("2006-01-02 15:04:05"))
+	}
+	var req, lim [2]float64
+	for _, c := range d.Containers {
+		if !c.Init {
+			req[0], req[1] = req[0]+float64(c.CPUReqm), req[1]+float64(c.MemReqBytes)
+			lim[0], lim[1] = lim[0]+float64(c.CPULimm), lim[1]+float64(c.MemLimBytes)
+		}
+	}
+	if len(d.Containers) == 0 { // not described: the first container's, as listed
+		req = [2]float64{float64(p.CPUReqm), float64(p.MemReqBytes)}
+		lim = [2]float64{float64(p.CPULimm), float64(p.MemLimBytes)}
+	}
+	var b strings.Builder
+	b.WriteString(kvLines(
+		"Pod", p.PodName,
+		"Namespace", p.Namespace,
+		"Node", dash(p.NodeName)+conditionNote(p.NodePressure),
+		"Phase", fmt.Sprintf("%s   ready %s   restarts %d", dash(p.Phase), dash(p.Ready), p.Restarts),
+		"QoS", dash(config.Or(d.QoS, p.QoS)),
+		"Owner", owner,
+		"IP", dash(d.IP),
+		"Service account", dash(d.ServiceAccount),
+		"Started", started,
+		"Labels", dash(labelList(p.Labels)),
+	))
+	b.WriteString("\n\n")
+	b.WriteString(kvLines(
+		"CPU", m.usage("cpu", float64(p.CPUm), req[0], lim[0], cores),
+		"Memory", m.usage("mem", float64(p.MemBytes), req[1], lim[1], bytesIEC),
+	))
+	b.WriteString("\n\n" + styles.Title.Render("Conditions") + "\n")
+	b.WriteString(conditionsTable(d.Conditions))
+	return b.String()
+}
+
+func (m Model) nodeDetailTab(tab string) string {
+	d, n := m.detail.node, m.detailNode()
+	switch tab {
+	case "Pods":
+		return m.nodePodsTable(d.Allocatable)
+	case "Labels":
+		if len(d.Labels) == 0 {
+			return styles.Faint.Render("no labels")
+		}
+		var lines []string
+		for _, k := range sortedNames(d.Labels) {
+			lines = append(lines, styles.Faint.Render(k+"=")+d.Labels[k])
+		}
+		return strings.Join(lines, "\n")
+	case "Events":
+		return eventsTable(d.Events)
+	}
+
+	status := "Ready"
+	if n.NotReady {
+		status = styles.Danger.Render("NotReady")
+	}
+	if d.Unschedulable {
+		status += ", " + styles.Warn.Render("SchedulingDisabled")
+	}
+	for _, c := range n.Pressure {
+		status += ", " + styles.Warn.Render(c)
+	}
+	created := "—"
+	if !d.Created.IsZero() {
+		created = podAge(d.Created) + " ago"
+	}
+	var b strings.Builder
+	b.WriteString(kvLines(
+		"Node", n.NodeName,
+		"Status", status,
+		"Kubelet", dash(config.Or(d.Kubelet, n.K8sVer)),
+		"OS", dash(strings.TrimSpace(d.OS+"  "+d.Kernel)),
+		"Runtime", dash(d.Runtime),
+		"Addresses", dash(strings.Join(d.Addresses, ", ")),
+		"Created", created,
+		"Taints", dash(strings.Join(d.Taints, ", ")),
+	))
+	b.WriteString("\n\n")
+	used := func(v float64, metric string) string {
+		s := m.severity(metric, v, 1)
+		return s.paint(fmt.Sprintf("%3.0f%%", v*100)) + " " + s.bar(v, 20)
+	}
+	c, a := d.Capacity, d.Allocatable
+	b.WriteString(textTable(
+		[]string{"RESOURCE", "CAPACITY", "ALLOCATABLE", "USED"},
+		[][]string{
+			{"cpu", num(c.CPUm, cores), num(a.CPUm, cores), used(n.CPUUsed, "node.cpu")},
+			{"memory", num(c.MemBytes, bytesIEC), num(a.MemBytes, bytesIEC), used(n.MEMUsed, "node.mem")},
+			{"pods", num(c.Pods, count), num(a.Pods, count), fmt.Sprint(n.Pods)},
+			{"ephemeral", num(c.DiskBytes, bytesIEC), num(a.DiskBytes, bytesIEC), ""},
+		}, nil))
+	b.WriteString("\n\n" + styles.Title.Render("Conditions") + "\n")
+	b.WriteString(conditionsTable(d.Conditions))
+	return b.String()
+}
+
+// nodePodsTable lists the node's pods by CPU, as shares of what the node
+// can allocate.
+func (m Model) nodePodsTable(alloc domain.Resources) string {
+	if len(m.detail.pods) == 0 {
+		return styles.Faint.Render("no pods")
+	}
+	rows := make([][]string, 0, len(m.detail.pods))
+	for _, p := range m.detail.pods {
+		cpuOf, memOf := float64(config.Or(alloc.CPUm, p.NodeCPUm)), float64(config.Or(alloc.MemBytes, p.NodeMemBytes))
+		rows = append(rows, []string{
+			p.PodName, p.Namespace,
+			milli(p.CPUm), m.severity("cpu/node", float64(p.CPUm), cpuOf).paint(percent(float64(p.CPUm), cpuOf)),
+			bytesIEC(float64(p.MemBytes)), m.severity("mem/node", float64(p.MemBytes), memOf).paint(percent(float64(p.MemBytes), memOf)),
+			p.Ready, p.Phase,
+		})
+	}
+	return textTable([]string{"POD", "NAMESPACE", "CPU", "OF NODE", "MEM", "OF NODE", "READY", "PHASE"}, rows, nil)
+}
+
+// detailCharts are the page's CPU and MEM charts, one above the other and
+// as tall as the page allows.
+func (m Model) detailCharts() string {
+	w, h := m.width-2, max(chartHeight, (m.detailHeight()-3)/2)
+	if m.detail.kind == ViewNodes {
+		n := m.detailNode()
+		cpu := trendChart("cpu", n.CPUTrend, share, w)
+		mem := trendChart("mem", n.MEMTrend, share, w)
+		cpu.Height, mem.Height = h, h
+		cpu.Max, mem.Max = 1, 1
+		return styles.Title.Render("CPU of allocatable") + "\n" + cpu.View() + "\n\n" +
+			styles.Title.Render("MEM of allocatable") + "\n" + mem.View()
+	}
+	p := m.detailPod()
+	cpu := trendChart("cpu", p.CPUTrend, cores, w)
+	cpu.Refs = bounds(float64(p.CPUReqm), float64(p.CPULimm))
+	mem := trendChart("mem", p.MemTrend, bytesIEC, w)
+	mem.Refs = bounds(float64(p.MemReqBytes), float64(p.MemLimBytes))
+	mem.Binary = true
+	cpu.Height, mem.Height = h, h
+	return styles.Title.Render("CPU") + "\n" + cpu.View() + "\n\n" +
+		styles.Title.Render("MEM") + "\n" + mem.View()
+}
+
+// usage is used against the request and limit, in the metric's colors.
+func (m Model) usage(metric string, used, req, lim float64, unit func(float64) string) string {
+	s := unit(used)
+	for _, of := range []struct {
+		name string
+		v    float64
+	}{{"req", req}, {"lim", lim}} {
+		if of.v <= 0 {
+			s += fmt.Sprintf("   %s —", of.name)
+			continue
+		}
+		sev := m.severity(metric+"/"+of.name, used, of.v)
+		s += fmt.Sprintf("   %s %s %s", of.name, unit(of.v), sev.paint(fmt.Sprintf("(%.0f%%)", used/of.v*100)))
+	}
+	return s
+}
+
+func containerBlock(c domain.ContainerDetail) string {
+	name := styles.Title.Render(c.Name)
+	if c.Init {
+		name += styles.Faint.Render(" (init)")
+	}
+	state := c.State
+	switch {
+	case strings.HasPrefix(c.State, "Waiting"):
+		state = styles.Danger.Render(c.State)
+	case c.State == "Running" && c.Ready:
+		state = styles.Good.Render("Running, ready")
+	case c.State == "Running":
+		state = styles.Warn.Render("Running, not ready")
+	}
+	restarts := fmt.Sprint(c.Restarts)
+	if c.LastExit != "" {
+		restarts += "   last exit: " + styles.Warn.Render(c.LastExit)
+	}
+	return name + "\n" + indent(kvLines(
+		"Image", c.Image,
+		"State", state,
+		"Restarts", restarts,
+		"CPU", fmt.Sprintf("req %s   lim %s", num(c.CPUReqm, cores), num(c.CPULimm, cores)),
+		"Memory", fmt.Sprintf("req %s   lim %s", num(c.MemReqBytes, bytesIEC), num(c.MemLimBytes, bytesIEC)),
+		"Liveness", dash(c.Liveness),
+		"Readiness", dash(c.Readiness),
+		"Startup", dash(c.Startup),
+	))
+}
+
+// positive are the conditions that are good when True; the others
+// (MemoryPressure, NetworkUnavailable...) are good when False.
+var positive = map[string]bool{"Ready": true, "ContainersReady": true, "Initialized": true, "PodScheduled": true, "PodReadyToStartContainers": true}
+
+func conditionsTable(cs []domain.Condition) string {
+	if len(cs) == 0 {
+		return styles.Faint.Render("none reported")
+	}
+	rows := make([][]string, len(cs))
+	tints := make([]lipgloss.Style, len(cs))
+	for i, c := range cs {
+		rows[i] = []string{c.Type, c.Status, podAge(c.Since), c.Reason, c.Message}
+		if (c.Status == "True") != positive[c.Type] {
+			tints[i] = styles.Warn
+		}
+	}
+	return textTable([]string{"TYPE", "STATUS", "SINCE", "REASON", "MESSAGE"}, rows, tints)
+}
+
+func eventsTable(es []domain.Event) string {
+	if len(es) == 0 {
+		return styles.Faint.Render("no recent events")
+	}
+	rows := make([][]string, len(es))
+	tints := make([]lipgloss.Style, len(es))
+	for i, e := range es {
+		rows[i] = []string{podAge(e.Last), e.Type, e.Reason, fmt.Sprintf("×%d", e.Count), e.Message}
+		if e.Type == "Warning" {
+			tints[i] = styles.Warn
+		}
+	}
+	return textTable([]string{"LAST", "TYPE", "REASON", "COUNT", "MESSAGE"}, rows, tints)
+}
+
+// kvLines lays out name/value pairs with the names in a faint column.
+func kvLines(kv ...string) string {
+	w := 0
+	for i := 0; i < len(kv); i += 2 {
+		w = max(w, len(kv[i]))
+	}
+	lines := make([]string, 0, len(kv)/2)
+	for i := 0; i < len(kv); i += 2 {
+		lines = append(lines, styles.Faint.Render(fmt.Sprintf("%-*s", w+2, kv[i]))+kv[i+1])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// textTable lines cells up under a header; tints, when set, style whole
+// rows.
+func textTable(header []string, rows [][]string, tints []lipgloss.Style) string {
+	ws := make([]int, len(header))
+	for i, h := range header {
+		ws[i] = len(h)
+	}
+	for _, r := range rows {
+		for i, c := range r {
+			ws[i] = max(ws[i], ansi.StringWidth(c))
+		}
+	}
+	line := func(cells []string) string {
+		var b strings.Builder
+		for i, c := range cells {
+			b.WriteString(c)
+			if i < len(cells)-1 {
+				b.WriteString(strings.Repeat(" ", ws[i]-ansi.StringWidth(c)+2))
+			}
+		}
+		return b.String()
+	}
+	out := []string{styles.Faint.Render(line(header))}
+	for i, r := range rows {
+		l := line(r)
+		if i < len(tints) {
+			l = tints[i].Render(l)
+		}
+		out = append(out, l)
+	}
+	return strings.Join(out, "\n")
+}
+
+func indent(s string) string { return "  " + strings.ReplaceAll(s, "\n", "\n  ") }
+
+func labelList(ls map[string]string) string { return strings.Join(labelPairs(ls), ", ") }
+
+// labelPairs are the labels as key=value, sorted by key.
+func labelPairs(ls map[string]string) []string {
+	parts := make([]string, 0, len(ls))
+	for _, k := range sortedNames(ls) {
+		parts = append(parts, k+"="+ls[k])
+	}
+	return parts
+}
+
+// num formats a request, limit or amount; 0 means not set.
+func num[T int | int64](v T, unit func(float64) string) string {
+	if v == 0 {
+		return "—"
+	}
+	return unit(float64(v))
+}
+
+func count(v float64) string { return fmt.Sprint(int(v)) }
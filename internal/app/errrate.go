@@ -0,0 +1,71 @@
+// internal/ui/app/errrate.go
+package app
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+)
+
+// sampleErrors points the error-rate sampler at the pods in view, in table
+// order, so the budget goes to the rows the user is looking at. Pods hidden
+// by a find or filter come last rather than not at all, so "err > 5" doesn't
+// stop sampling the pods it hides.
+func (m Model) sampleErrors() {
+	if m.errRate == nil {
+		return
+	}
+	targets := make([]domain.LogsTarget, 0, len(m.allPods))
+	shown := map[string]bool{}
+	for _, p := range m.pods {
+		targets = append(targets, podTarget(p))
+		shown[p.Namespace+"/"+p.PodName] = true
+	}
+	for _, p := range m.allPods {
+		if !shown[p.Namespace+"/"+p.PodName] {
+			targets = append(targets, podTarget(p))
+		}
+	}
+	m.errRate.Sync(m.ctx, targets)
+}
+
+func (m Model) stopSampler() {
+	if m.errRate != nil {
+		m.errRate.Close()
+	}
+}
+
+func podTarget(p domain.PodMetric) domain.LogsTarget {
+	return domain.LogsTarget{Namespace: p.Namespace, Kind: "Pod", Name: p.PodName, Container: p.Container}
+}
+
+// errCell is the ERR/min column; "—" marks pods outside the sampling budget.
+func (m Model) errCell(p domain.PodMetric) string {
+	n, _, ok := m.errRate.Rate(podTarget(p))
+	if !ok {
+		return "—"
+	}
+	return fmt.Sprintf("%5d", n)
+}
+
+// errTrend is the info panel line under the usage bars, scaled to its
+// own peak so a burst stands out whatever the baseline.
+func (m Model) errTrend(p domain.PodMetric, width int) string {
+	if m.errRate == nil {
+		return ""
+	}
+	n, series, ok := m.errRate.Rate(podTarget(p))
+	if !ok {
+		return "\nTrend ERR: not sampled (over budget)"
+	}
+	peak := 1.0
+	for _, v := range series {
+		peak = math.Max(peak, v)
+	}
+	norm := make([]float64, len(series))
+	for i, v := range series {
+		norm[i] = v / peak
+	}
+	return fmt.Sprintf("\nTrend ERR: %s  %d/min (peak %.0f/min, 10m)", spark(norm, width), n, peak)
+}
@@ -0,0 +1,169 @@
+// internal/ui/app/find.go
+package app
+
+import (
+	"strings"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/styles"
+)
+
+// fuzzyMatch reports whether the runes of pat appear in s in order, ignoring
+// case, and where. The leftmost match is tightened so matched characters
+// cluster together, which is what reads as "the match" when highlighted.
+func fuzzyMatch(pat, s string) ([]int, bool) {
+	p := []rune(strings.ToLower(pat))
+	r := []rune(strings.ToLower(s))
+	if len(p) == 0 {
+		return nil, true
+	}
+	pos := make([]int, 0, len(p))
+	j := 0
+	for i := 0; i < len(r) && j < len(p); i++ {
+		if r[i] == p[j] {
+			pos = append(pos, i)
+			j++
+		}
+	}
+	if j < len(p) {
+		return nil, false
+	}
+	// walk back from the end so each rune sits as close as possible to the
+	// next one: "api" in "a-pod-api" highlights the trailing "api"
+	for k := len(p) - 2; k >= 0; k-- {
+		for i := pos[k+1] - 1; i > pos[k]; i-- {
+			if r[i] == p[k] {
+				pos[k] = i
+				break
+			}
+		}
+	}
+	return pos, true
+}
+
+// findTerms splits the find text on spaces; every term must match one of a
+// row's fields.
+func findTerms(q string) []string {
+	return strings.Fields(q)
+}
+
+func matchesFind(terms []string, fields ...string) bool {
+	for _, t := range terms {
+		ok := false
+		for _, f := range fields {
+			if _, ok = fuzzyMatch(t, f); ok {
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func podCell(p domain.PodMetric) string {
+	return p.PodName + " (" + p.Container + ")"
+}
+
+func filterPods(all []domain.PodMetric, q string) []domain.PodMetric {
+	terms := findTerms(q)
+	if len(terms) == 0 {
+		return all
+	}
+	out := make([]domain.PodMetric, 0, len(all))
+	for _, p := range all {
+		if matchesFind(terms, podCell(p), p.NodeName, p.Namespace) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func filterNodes(all []domain.NodeMetric, q string) []domain.NodeMetric {
+	terms := findTerms(q)
+	if len(terms) == 0 {
+		return all
+	}
+	out := make([]domain.NodeMetric, 0, len(all))
+	for _, n := range all {
+		if matchesFind(terms, n.NodeName) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// highlight marks the characters of s matched by any find term.
+func highlight(s, q string) string {
+	terms := findTerms(q)
+	if len(terms) == 0 {
+		return s
+	}
+	hit := map[int]bool{}
+	for _, t := range terms {
+		if pos, ok := fuzzyMatch(t, s); ok {
+			for _, i := range pos {
+				hit[i] = true
+			}
+		}
+	}
+	if len(hit) == 0 {
+		return s
+	}
+	var b strings.Builder
+	var run []rune
+	flush := func(matched bool) {
+		if len(run) == 0 {
+			return
+		}
+		if matched {
+			b.WriteString(styles.Match.Render(string(run)))
+		} else {
+			b.WriteString(string(run))
+		}
+		run = run[:0]
+	}
+	rs := []rune(s)
+	for i, c := range rs {
+		if i > 0 && hit[i] != hit[i-1] {
+			flush(hit[i-1])
+		}
+		run = append(run, c)
+	}
+	flush(hit[len(rs)-1])
+	return b.String()
+}
+
+// setFind narrows the tables to rows matching q and redraws them.
+func (m *Model) setFind(q string) {
+	m.find = q
+	m.applyFilters()
+	m.rebuildTable()
+}
+
+// applyFilters derives the visible rows from the last fetch: the fuzzy find
+// and the filter expression both have to match.
+func (m *Model) applyFilters() {
+	pods := filterPods(m.allPods, m.find)
+	if m.podExpr != nil {
+		keep := make([]domain.PodMetric, 0, len(pods))
+		for i := range pods {
+			if m.podExpr.Match(podEnv{m, &pods[i]}) {
+				keep = append(keep, pods[i])
+			}
+		}
+		pods = keep
+	}
+	nodes := filterNodes(m.allNodes, m.find)
+	if m.nodeExpr != nil {
+		keep := make([]domain.NodeMetric, 0, len(nodes))
+		for i := range nodes {
+			if m.nodeExpr.Match(nodeEnv{&nodes[i]}) {
+				keep = append(keep, nodes[i])
+			}
+		}
+		nodes = keep
+	}
+	m.pods, m.nodes = pods, nodes
+}
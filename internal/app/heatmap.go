@@ -0,0 +1,297 @@
+// internal/ui/app/heatmap.go
+package app
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/styles"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/widgets"
+)
+
+// heatmap is the full-screen map of node utilization over time, a row per
+// node and a column per time bucket, drawn from the trends ListNodes keeps.
+// Nodes are fetched on open and on every tick while it is shown, apart from
+// the table's, so the table keeps its cursor.
+type heatmap struct {
+	on    bool
+	mem   bool // MEM instead of CPU
+	group int  // index in heatGroups
+
+	row, top int
+	back     int // cursor column, counted back from the newest bucket
+
+	loaded bool
+	err    error
+	nodes  []domain.NodeMetric
+}
+
+type heatmapMsg struct {
+	nodes []domain.NodeMetric
+	err   error
+}
+
+// heatGroups are the ways rows can be grouped: by the first of the labels
+// a node has.
+var heatGroups = []struct {
+	name   string
+	labels []string
+}{
+	{"none", nil},
+	{"zone", []string{"topology.kubernetes.io/zone", "failure-domain.beta.kubernetes.io/zone"}},
+	{"pool", []string{"eks.amazonaws.com/nodegroup", "karpenter.sh/nodepool", "cloud.google.com/gke-nodepool", "kubernetes.azure.com/agentpool", "agentpool"}},
+}
+
+// openHeatmap shows the map; the metric and grouping stay as last left.
+func (m *Model) openHeatmap() tea.Cmd {
+	m.heat.on, m.heat.loaded = true, false
+	m.heat.row, m.heat.top, m.heat.back = 0, 0, 0
+	return m.fetchHeatmap()
+}
+
+func (m Model) fetchHeatmap() tea.Cmd {
+	return func() tea.Msg {
+		n, err := m.repoM.ListNodes(m.ctx)
+		return heatmapMsg{n, err}
+	}
+}
+
+func (m *Model) setHeatmap(msg heatmapMsg) {
+	h := &m.heat
+	if !h.on {
+		return
+	}
+	h.loaded, h.err = true, msg.err
+	if msg.err == nil {
+		sort.SliceStable(msg.nodes, func(i, j int) bool { return msg.nodes[i].NodeName < msg.nodes[j].NodeName })
+		h.nodes = msg.nodes
+	}
+}
+
+// heatmapHeight is what the map gets: all but the header, the title, the
+// cursor line, the legend and the footer.
+func (m Model) heatmapHeight() int { return max(4, m.height-5) }
+
+// heatGrid buckets the nodes' trends into at most cols columns. A bucket
+// is one refresh long until the history doesn't fit, then as long as it
+// takes. Grouped, each group gets a heading row with its nodes' mean.
+func (m Model) heatGrid(cols int) (rows []widgets.HeatRow, times []time.Time, step time.Duration) {
+	h := m.heat
+	trend := func(n domain.NodeMetric) domain.Trend {
+		if h.mem {
+			return n.MEMTrend
+		}
+		return n.CPUTrend
+	}
+	var first, last time.Time
+	for _, n := range h.nodes {
+		if ts := trend(n).Times; len(ts) > 0 {
+			if first.IsZero() || ts[0].Before(first) {
+				first = ts[0]
+			}
+			if ts[len(ts)-1].After(last) {
+				last = ts[len(ts)-1]
+			}
+		}
+	}
+	if last.IsZero() || cols < 1 {
+		return nil, nil, 0
+	}
+	step = m.refresh
+	if step < time.Second {
+		step = time.Second
+	}
+	span := last.Sub(first) + step // the first sample needs a bucket too
+	if span > time.Duration(cols)*step {
+		step = (span / time.Duration(cols)).Round(time.Second) + time.Second
+	}
+	n := min(cols, int((span+step-1)/step))
+	start := last.Add(-time.Duration(n) * step)
+	for i := 0; i < n; i++ {
+		times = append(times, start.Add(time.Duration(i)*step))
+	}
+
+	buckets := func(nm domain.NodeMetric) []float64 {
+		t := trend(nm)
+		return widgets.Buckets(t.Values, t.Times, last, step, n)
+	}
+	g := heatGroups[h.group]
+	if g.labels == nil {
+		for _, nm := range h.nodes {
+			rows = append(rows, widgets.HeatRow{Label: nm.NodeName, Values: buckets(nm)})
+		}
+		return rows, times, step
+	}
+	members := map[string][]domain.NodeMetric{}
+	for _, nm := range h.nodes {
+		v := "(none)"
+		for _, l := range g.labels {
+			if lv, ok := nm.Labels[l]; ok {
+				v = lv
+				break
+			}
+		}
+		members[v] = append(members[v], nm)
+	}
+	for _, v := range sortedNames(members) {
+		head := widgets.HeatRow{Label: fmt.Sprintf("%s (%d)", v, len(members[v])), Group: true}
+		var nodes []widgets.HeatRow
+		for _, nm := range members[v] {
+			nodes = append(nodes, widgets.HeatRow{Label: "  " + nm.NodeName, Values: buckets(nm)})
+		}
+		head.Values = meanOf(nodes, n)
+		rows = append(append(rows, head), nodes...)
+	}
+	return rows, times, step
+}
+
+// meanOf is the column means of rows, skipping empty buckets.
+func meanOf(rows []widgets.HeatRow, n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		sum, cnt := 0.0, 0
+		for _, r := range rows {
+			if !math.IsNaN(r.Values[i]) {
+				sum += r.Values[i]
+				cnt++
+			}
+		}
+		out[i] = math.NaN()
+		if cnt > 0 {
+			out[i] = sum / float64(cnt)
+		}
+	}
+	return out
+}
+
+// heatChart is the map as shown, cursor clamped to the grid.
+func (m *Model) heatChart() (widgets.Heatmap, time.Duration) {
+	h := &m.heat
+	hm := widgets.Heatmap{
+		Width:   m.width - 2,
+		Height:  m.heatmapHeight(),
+		Heading: styles.Title,
+		Cursor:  styles.Selected,
+		Axis:    styles.Faint,
+	}
+	metric := map[bool]string{false: "node.cpu", true: "node.mem"}[h.mem]
+	hm.Color = func(v float64) lipgloss.Style { return m.severity(metric, v, 1).style(styles.Good) }
+
+	lw := 0
+	for _, n := range h.nodes {
+		lw = max(lw, len(n.NodeName)+2)
+	}
+	var step time.Duration
+	hm.Rows, hm.Times, step = m.heatGrid(hm.Width - min(lw, max(8, hm.Width/3)) - 1)
+	h.row = max(0, clamp(h.row, 0, len(hm.Rows)-1))
+	h.back = clamp(h.back, 0, max(0, len(hm.Times)-1))
+	hm.Row, hm.Col = h.row, len(hm.Times)-1-h.back
+	h.top = hm.Scroll(h.top)
+	hm.Top = h.top
+	return hm, step
+}
+
+// updateHeatmap handles keys while the heatmap is shown.
+func (m Model) updateHeatmap(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	k := m.keys
+	h := &m.heat
+	page := max(1, m.heatmapHeight()-2)
+	n, given := m.popCount()
+	switch {
+	case key.Matches(msg, k.Back):
+		h.on = false
+	case key.Matches(msg, k.Quit):
+		h.on = false
+		return m.Update(msg)
+	case key.Matches(msg, k.HeatMetric):
+		h.mem = !h.mem
+	case key.Matches(msg, k.HeatGroup):
+		h.group = (h.group + 1) % len(heatGroups)
+		h.row, h.top = 0, 0
+	case key.Matches(msg, k.Earlier):
+		h.back += n
+	case key.Matches(msg, k.Later):
+		h.back = max(0, h.back-n)
+	case key.Matches(msg, k.Up):
+		h.row = max(0, h.row-n)
+	case key.Matches(msg, k.Down):
+		h.row += n
+	case key.Matches(msg, k.HalfUp):
+		h.row = max(0, h.row-n*max(1, page/2))
+	case key.Matches(msg, k.HalfDown):
+		h.row += n * max(1, page/2)
+	case key.Matches(msg, k.PageUp):
+		h.row = max(0, h.row-n*page)
+	case key.Matches(msg, k.PageDown):
+		h.row += n * page
+	case key.Matches(msg, k.Top):
+		h.row = 0
+	case key.Matches(msg, k.Bottom):
+		h.row = math.MaxInt32
+		if given {
+			h.row = n - 1
+		}
+	}
+	m.heatChart() // clamps the cursor
+	return m, nil
+}
+
+// heatmapView is the page: a title, the value under the cursor, the map and
+// its legend.
+func (m Model) heatmapView() string {
+	h := m.heat
+	hm, step := m.heatChart()
+	metric := map[bool]string{false: "CPU", true: "MEM"}[h.mem]
+	title := styles.Title.Render("Heatmap: node "+metric+" of allocatable") +
+		styles.Faint.Render("   group: "+heatGroups[h.group].name)
+	if step > 0 {
+		title += styles.Faint.Render(fmt.Sprintf("   bucket: %s", step))
+	}
+
+	var cur string
+	switch {
+	case h.err != nil:
+		cur = styles.Danger.Render(h.err.Error())
+	case !h.loaded:
+		cur = styles.Faint.Render("loading…")
+	case len(hm.Rows) == 0:
+		cur = styles.Faint.Render("no node history yet")
+	default:
+		r := hm.Rows[hm.Row]
+		from := hm.Times[hm.Col]
+		at := fmt.Sprintf("%s–%s", from.Format("15:04:05"), from.Add(step).Format("15:04:05"))
+		v := "no sample"
+		if x := r.Values[hm.Col]; !math.IsNaN(x) {
+			v = m.severity("node."+strings.ToLower(metric), x, 1).paint(fmt.Sprintf("%.1f%%", x*100))
+		}
+		name := strings.TrimSpace(r.Label)
+		if r.Group {
+			name = heatGroups[h.group].name + " " + name + " mean"
+		}
+		cur = fmt.Sprintf("%s   %s %s   %s", styles.TabActive.Render(name), metric, v, styles.Faint.Render(at))
+	}
+
+	var legend []string
+	for _, v := range []float64{0, 0.25, 0.5, 0.75} {
+		legend = append(legend, fmt.Sprintf("%s ≥%.0f%%", widgets.Shade(v), v*100))
+	}
+	body := title + "\n" + cur
+	if len(hm.Rows) > 0 {
+		body += "\n" + hm.View() + "\n" +
+			styles.Faint.Render(strings.Join(legend, "  ")+"   colored by the node."+strings.ToLower(metric)+" thresholds")
+	}
+	lines := strings.Split(body, "\n")
+	for i, l := range lines {
+		lines[i] = ansi.Truncate(l, m.width-2, "…")
+	}
+	return lipgloss.NewStyle().Padding(0, 1).Height(m.height - 2).MaxHeight(m.height - 2).Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,476 @@
+// internal/ui/app/keys.go
+package app
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/config"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/styles"
+)
+
+// keyMode is where a binding applies; help and the footer only list the
+// bindings of the current mode.
+type keyMode uint16
+
+const (
+	modeTable keyMode = 1 << iota // the Pods table
+	modeInfo                      // the Pods table with the info panel open
+	modeNodes                     // the Nodes table, info panel or not
+	modeLogs
+	modePicker
+	modeChooser
+	modeDetail
+	modeHeatmap
+	modeTreemap
+	modeDashboard
+
+	modeMotion = modeTable | modeInfo | modeNodes | modeLogs | modePicker | modeChooser | modeDetail | modeHeatmap | modeTreemap | modeDashboard
+	modeMain   = modeTable | modeInfo | modeNodes
+	modePods   = modeTable | modeInfo
+)
+
+// keyMap holds every binding. Motions take a count: "5j", "3ctrl+d",
+// "10G" jumps to row 10.
+type keyMap struct {
+	Up, Down, HalfUp, HalfDown, PageUp, PageDown, Top, Bottom key.Binding
+
+	Help, Quit, Back key.Binding
+
+	Namespace, SwitchView, Info, Detail, Expand, Logs, WorkloadLogs, TailSelector, Heatmap, Treemap key.Binding
+	NodeDetail, Dashboard, Pin, LabelSelector, FieldSelector, Find, Command, Columns                key.Binding
+	Sort, SortDirection                                                                             key.Binding
+
+	LogSearch, LogNext, LogPrev, LogFilter, LogLevel, LogRange key.Binding
+	LogPrevious, LogRaw, LogExport, LogExportAll               key.Binding
+
+	Select, Toggle, MoveUp, MoveDown, Wider, Narrower, Reset, Save key.Binding
+
+	NextTab, PrevTab, Manifest, Describe, HideFields key.Binding
+
+	Earlier, Later, HeatMetric, HeatGroup key.Binding
+
+	Left, Right, ZoomIn, ZoomOut, TreeMetric, TreeMeasure key.Binding
+}
+
+func bind(help string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(keysHelp(keys), help))
+}
+
+// keysHelp is how keys are shown in help: "up/k", "space".
+func keysHelp(keys []string) string {
+	shown := make([]string, len(keys))
+	for i, k := range keys {
+		shown[i] = strings.ReplaceAll(k, " ", "space")
+	}
+	return strings.Join(shown, "/")
+}
+
+func defaultKeyMap() keyMap {
+	return keyMap{
+		Up:       bind("up", "up", "k"),
+		Down:     bind("down", "down", "j"),
+		HalfUp:   bind("½ page up", "ctrl+u"),
+		HalfDown: bind("½ page down", "ctrl+d"),
+		PageUp:   bind("page up", "pgup", "ctrl+b"),
+		PageDown: bind("page down", "pgdown", "ctrl+f", " "),
+		Top:      bind("first", "g", "home"),
+		Bottom:   bind("last (nG: row n)", "G", "end"),
+
+		Help: bind("help", "?"),
+		Quit: bind("quit", "q"),
+		Back: bind("back / close", "esc"),
+
+		Namespace:     bind("namespace", "n"),
+		SwitchView:    bind("pods/nodes", "tab"),
+		Info:          bind("info panel", "i"),
+		Detail:        bind("details", "enter", "o"),
+		NodeDetail:    bind("details", "o"),
+		Expand:        bind("expand node", "enter"),
+		Logs:          bind("logs", "l"),
+		WorkloadLogs:  bind("workload logs", "W"),
+		TailSelector:  bind("tail selector", "T"),
+		Heatmap:       bind("node heatmap", "H"),
+		Treemap:       bind("namespace treemap", "M"),
+		Dashboard:     bind("dashboard", "B"),
+		Pin:           bind("pin pod logs", "p"),
+		LabelSelector: bind("label selector", "L"),
+		FieldSelector: bind("field selector", "F"),
+		Find:          bind("find", "/"),
+		Command:       bind("command", ":"),
+		Columns:       bind("columns", "c"),
+		Sort:          bind("sort column", "s"),
+		SortDirection: bind("sort direction", "S"),
+
+		LogSearch:    bind("search", "/"),
+		LogNext:      bind("next match", "n"),
+		LogPrev:      bind("previous match", "N"),
+		LogFilter:    bind("filter", "f"),
+		LogLevel:     bind("level", "w"),
+		LogRange:     bind("range", "t"),
+		LogPrevious:  bind("previous container", "p"),
+		LogRaw:       bind("raw", "r"),
+		LogExport:    bind("export view", "e"),
+		LogExportAll: bind("export all", "E"),
+
+		Select:   bind("select", "enter"),
+		Toggle:   bind("toggle", " ", "x"),
+		MoveUp:   bind("move up", "K", "shift+up"),
+		MoveDown: bind("move down", "J", "shift+down"),
+		Wider:    bind("wider", "+", "=", "right"),
+		Narrower: bind("narrower / auto", "-", "left"),
+		Reset:    bind("reset", "r"),
+		Save:     bind("apply and save", "w"),
+
+		NextTab:    bind("next tab", "tab", "right"),
+		PrevTab:    bind("previous tab", "shift+tab", "left"),
+		Manifest:   bind("yaml", "y"),
+		Describe:   bind("describe", "D"),
+		HideFields: bind("hide managedFields/status", "m"),
+
+		Earlier:    bind("earlier", "left", "h"),
+		Later:      bind("later", "right", "l"),
+		HeatMetric: bind("cpu/mem", "m"),
+		HeatGroup:  bind("group by zone/pool", "z"),
+
+		Left:        bind("left", "left", "h"),
+		Right:       bind("right", "right", "l"),
+		ZoomIn:      bind("zoom in", "enter"),
+		ZoomOut:     bind("zoom out", "backspace"),
+		TreeMetric:  bind("cpu/mem", "m"),
+		TreeMeasure: bind("usage/requests", "r"),
+	}
+}
+
+// action is a binding as config and help see it.
+type action struct {
+	name  string
+	modes keyMode
+	b     *key.Binding
+}
+
+// actions lists every binding in help order.
+func (k *keyMap) actions() []action {
+	return []action{
+		{"up", modeMotion, &k.Up},
+		{"down", modeMotion, &k.Down},
+		{"half-page-up", modeMain | modeLogs | modeDetail | modeHeatmap | modeDashboard, &k.HalfUp},
+		{"half-page-down", modeMain | modeLogs | modeDetail | modeHeatmap | modeDashboard, &k.HalfDown},
+		{"page-up", modeMain | modeLogs | modePicker | modeDetail | modeHeatmap | modeDashboard, &k.PageUp},
+		{"page-down", modeMain | modeLogs | modePicker | modeDetail | modeHeatmap | modeDashboard, &k.PageDown},
+		{"top", modeMain | modeLogs | modePicker | modeDetail | modeHeatmap | modeDashboard, &k.Top},
+		{"bottom", modeMain | modeLogs | modePicker | modeDetail | modeHeatmap | modeDashboard, &k.Bottom},
+
+		{"find", modeMain, &k.Find},
+		{"command", modeMain, &k.Command},
+		{"switch-view", modeMain, &k.SwitchView},
+		{"namespace", modeMain, &k.Namespace},
+		{"info", modeMain, &k.Info},
+		{"detail", modePods | modeDashboard, &k.Detail},
+		{"node-detail", modeNodes, &k.NodeDetail},
+		{"expand", modeNodes, &k.Expand},
+		{"logs", modeMain, &k.Logs},
+		{"workload-logs", modeMain, &k.WorkloadLogs},
+		{"tail-selector", modeMain, &k.TailSelector},
+		{"heatmap", modeMain, &k.Heatmap},
+		{"treemap", modeMain, &k.Treemap},
+		{"dashboard", modeMain, &k.Dashboard},
+		{"pin", modeMain | modeDashboard, &k.Pin},
+		{"label-selector", modeMain, &k.LabelSelector},
+		{"field-selector", modeMain, &k.FieldSelector},
+		{"columns", modeMain, &k.Columns},
+		{"sort", modeMain, &k.Sort},
+		{"sort-direction", modeMain, &k.SortDirection},
+
+		{"log-search", modeLogs | modeDetail, &k.LogSearch},
+		{"log-next", modeLogs | modeDetail, &k.LogNext},
+		{"log-prev", modeLogs | modeDetail, &k.LogPrev},
+		{"log-filter", modeLogs, &k.LogFilter},
+		{"log-level", modeLogs, &k.LogLevel},
+		{"log-range", modeLogs, &k.LogRange},
+		{"log-previous", modeLogs, &k.LogPrevious},
+		{"log-raw", modeLogs, &k.LogRaw},
+		{"log-export", modeLogs, &k.LogExport},
+		{"log-export-all", modeLogs, &k.LogExportAll},
+
+		{"select", modePicker | modeChooser, &k.Select},
+		{"toggle", modeChooser, &k.Toggle},
+		{"move-up", modeChooser, &k.MoveUp},
+		{"move-down", modeChooser, &k.MoveDown},
+		{"wider", modeChooser, &k.Wider},
+		{"narrower", modeChooser, &k.Narrower},
+		{"reset", modeChooser, &k.Reset},
+		{"save", modeChooser, &k.Save},
+
+		{"next-tab", modeDetail, &k.NextTab},
+		{"prev-tab", modeDetail, &k.PrevTab},
+		{"yaml", modeMain | modeDetail, &k.Manifest},
+		{"describe", modeMain | modeDetail, &k.Describe},
+		{"hide-fields", modeDetail, &k.HideFields},
+
+		{"earlier", modeHeatmap, &k.Earlier},
+		{"later", modeHeatmap, &k.Later},
+		{"heat-metric", modeHeatmap, &k.HeatMetric},
+		{"heat-group", modeHeatmap, &k.HeatGroup},
+
+		{"left", modeTreemap, &k.Left},
+		{"right", modeTreemap, &k.Right},
+		{"zoom-in", modeTreemap, &k.ZoomIn},
+		{"zoom-out", modeTreemap, &k.ZoomOut},
+		{"tree-metric", modeTreemap, &k.TreeMetric},
+		{"tree-measure", modeTreemap, &k.TreeMeasure},
+
+		{"back", modeMotion, &k.Back},
+		{"help", modeMain | modeLogs | modeDetail | modeHeatmap | modeTreemap | modeDashboard, &k.Help},
+		{"quit", modeMain | modePicker | modeDetail | modeHeatmap | modeTreemap | modeDashboard, &k.Quit},
+	}
+}
+
+// newKeyMap is the default keymap with config overrides applied; an
+// override replaces the action's keys, freeing the defaults.
+func newKeyMap(over map[string]config.Keys) keyMap {
+	k := defaultKeyMap()
+	for _, a := range k.actions() {
+		if keys, ok := over[a.name]; ok {
+			a.b.SetKeys(keys...)
+			a.b.SetHelp(keysHelp(keys), a.b.Help().Desc)
+		}
+	}
+	return k
+}
+
+func actionNames() []string {
+	k := defaultKeyMap()
+	var names []string
+	for _, a := range k.actions() {
+		names = append(names, a.name)
+	}
+	return names
+}
+
+// checkKeys reports unknown actions and keys bound twice in one mode, by
+// the config or by the defaults it leaves in place.
+func checkKeys(path string, over map[string]config.Keys) error {
+	names := actionNames()
+	for name := range over {
+		if !contains(names, name) {
+			return fmt.Errorf("%s: unknown action %q (have: %s)", path, name, strings.Join(names, ", "))
+		}
+	}
+	k := newKeyMap(over)
+	acts := k.actions()
+	for i, a := range acts {
+		for _, b := range acts[:i] {
+			if a.modes&b.modes == 0 {
+				continue
+			}
+			for _, key := range a.b.Keys() {
+				if contains(b.b.Keys(), key) {
+					return fmt.Errorf("%s: %q is bound to both %s and %s", path, key, b.name, a.name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// keyMode is the mode the next key is handled in.
+func (m Model) keyMode() keyMode {
+	switch {
+	case m.chooser.open():
+		return modeChooser
+	case m.picker.open():
+		return modePicker
+	case m.detail.open():
+		return modeDetail
+	case m.heat.on:
+		return modeHeatmap
+	case m.tree.on:
+		return modeTreemap
+	case m.dash.on:
+		return modeDashboard
+	case m.logsOpen:
+		return modeLogs
+	case m.view == ViewNodes:
+		return modeNodes
+	case m.infoOpen:
+		return modeInfo
+	}
+	return modeTable
+}
+
+// modeBindings are the enabled bindings of mode, in help order.
+func (m Model) modeBindings(mode keyMode) []key.Binding {
+	var out []key.Binding
+	for _, a := range m.keys.actions() {
+		if a.modes&mode != 0 && a.b.Enabled() {
+			out = append(out, *a.b)
+		}
+	}
+	return out
+}
+
+// shortHelp is the footer of each mode.
+func (m Model) shortHelp() []key.Binding {
+	k := m.keys
+	switch m.keyMode() {
+	case modeLogs:
+		return []key.Binding{k.Help, k.LogSearch, k.LogNext, k.LogFilter, k.LogLevel, k.LogRange, k.LogPrevious, k.LogRaw, k.LogExport, k.Back}
+	case modePicker:
+		return []key.Binding{k.Up, k.Down, k.Select, k.Back}
+	case modeChooser:
+		return []key.Binding{k.Toggle, k.MoveDown, k.MoveUp, k.Wider, k.Narrower, k.Reset, k.Select, k.Save, k.Back}
+	case modeDetail:
+		return []key.Binding{k.Help, k.NextTab, k.PrevTab, k.Manifest, k.Describe, k.LogSearch, k.LogNext, k.Back, k.Quit}
+	case modeHeatmap:
+		return []key.Binding{k.Help, k.Up, k.Down, k.Earlier, k.Later, k.HeatMetric, k.HeatGroup, k.Back, k.Quit}
+	case modeTreemap:
+		return []key.Binding{k.Help, k.ZoomIn, k.ZoomOut, k.TreeMetric, k.TreeMeasure, k.Back, k.Quit}
+	case modeDashboard:
+		return []key.Binding{k.Help, k.Up, k.Down, k.Pin, k.Detail, k.HalfDown, k.HalfUp, k.Back, k.Quit}
+	case modeNodes:
+		return []key.Binding{k.Help, k.Find, k.Command, k.SwitchView, k.Namespace, k.Info, k.Expand, k.NodeDetail, k.Logs, k.Columns, k.Sort, k.Back, k.Quit}
+	}
+	return []key.Binding{k.Help, k.Find, k.Command, k.SwitchView, k.Namespace, k.Info, k.Detail, k.Logs, k.Columns, k.Sort, k.Back, k.Quit}
+}
+
+func (m Model) helpModel() help.Model {
+	h := help.New()
+	h.Width = m.width
+	h.Styles.ShortKey = styles.Footer.Bold(true)
+	h.Styles.ShortDesc = styles.Footer
+	h.Styles.ShortSeparator = styles.Faint
+	h.Styles.FullKey = styles.TabActive
+	h.Styles.FullDesc = lipgloss.NewStyle()
+	h.Styles.FullSeparator = styles.Faint
+	h.Styles.Ellipsis = styles.Faint
+	return h
+}
+
+func (m Model) footerHelp() string {
+	return m.helpModel().ShortHelpView(m.shortHelp())
+}
+
+// chooserHelp is the chooser's own two-line key hint.
+func (m Model) chooserHelp() string {
+	bs := m.shortHelp()
+	h := m.helpModel()
+	return h.ShortHelpView(bs[:len(bs)/2]) + "\n" + h.ShortHelpView(bs[len(bs)/2:])
+}
+
+// renderHelp is the "?" overlay: every binding of the mode help was opened
+// from, in columns.
+func (m Model) renderHelp(width, height int) string {
+	names := map[keyMode]string{modeTable: "pods", modeInfo: "pods + info", modeNodes: "nodes", modeLogs: "logs", modePicker: "picker", modeChooser: "columns", modeDetail: "details", modeHeatmap: "heatmap", modeTreemap: "treemap", modeDashboard: "dashboard"}
+	bs := m.modeBindings(m.helpMode)
+	rows := max(8, min(12, height-8))
+	var groups [][]key.Binding
+	for len(bs) > 0 {
+		n := min(rows, len(bs))
+		groups = append(groups, bs[:n])
+		bs = bs[n:]
+	}
+	body := lipgloss.JoinVertical(lipgloss.Left,
+		styles.Title.Render(" Keys: "+names[m.helpMode]+" (any key closes) "),
+		"",
+		m.helpModel().FullHelpView(groups),
+		"",
+		styles.Faint.Render("motions take a count: 5j, 3ctrl+d, 10G"),
+	)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, styles.Popup.Render(body))
+}
+
+// takeCount folds a digit into the pending count; it reports whether the
+// key was consumed. A leading 0 is not a count.
+func (m *Model) takeCount(msg tea.KeyMsg) bool {
+	s := msg.String()
+	if len(s) != 1 || s[0] < '0' || s[0] > '9' || (s == "0" && m.count == 0) {
+		return false
+	}
+	m.count = min(m.count*10+int(s[0]-'0'), 99999)
+	return true
+}
+
+// popCount returns the pending count (1 if none) and clears it.
+func (m *Model) popCount() (n int, given bool) {
+	n, given = max(1, m.count), m.count > 0
+	m.count = 0
+	return n, given
+}
+
+// moveTable applies a motion to the table; it reports whether msg was one.
+func (m *Model) moveTable(msg tea.KeyMsg) bool {
+	k := m.keys
+	page := max(1, m.table.Height()-1)
+	n, given := m.popCount()
+	switch {
+	case key.Matches(msg, k.Up):
+		m.table.MoveUp(n)
+	case key.Matches(msg, k.Down):
+		m.table.MoveDown(n)
+	case key.Matches(msg, k.HalfUp):
+		m.table.MoveUp(n * max(1, page/2))
+	case key.Matches(msg, k.HalfDown):
+		m.table.MoveDown(n * max(1, page/2))
+	case key.Matches(msg, k.PageUp):
+		m.table.MoveUp(n * page)
+	case key.Matches(msg, k.PageDown):
+		m.table.MoveDown(n * page)
+	case key.Matches(msg, k.Top):
+		m.table.SetCursor(0)
+	case key.Matches(msg, k.Bottom):
+		if given {
+			m.table.SetCursor(n - 1)
+		} else {
+			m.table.SetCursor(len(m.table.Rows()) - 1)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// moveViewport applies a motion to a viewport, the detail page.
+func (m *Model) moveViewport(vp *viewport.Model, msg tea.KeyMsg) bool {
+	top, ok := m.scrollTo(msg, vp.YOffset, vp.Height)
+	if ok {
+		vp.SetYOffset(top)
+	}
+	return ok
+}
+
+// scrollTo is where a motion moves a pane of h rows showing from row top:
+// the new first row, left for the caller to clamp.
+func (m *Model) scrollTo(msg tea.KeyMsg, top, h int) (int, bool) {
+	k := m.keys
+	n, given := m.popCount()
+	switch {
+	case key.Matches(msg, k.Up):
+		return top - n, true
+	case key.Matches(msg, k.Down):
+		return top + n, true
+	case key.Matches(msg, k.HalfUp):
+		return top - n*max(1, h/2), true
+	case key.Matches(msg, k.HalfDown):
+		return top + n*max(1, h/2), true
+	case key.Matches(msg, k.PageUp):
+		return top - n*max(1, h), true
+	case key.Matches(msg, k.PageDown):
+		return top + n*max(1, h), true
+	case key.Matches(msg, k.Top):
+		return 0, true
+	case key.Matches(msg, k.Bottom):
+		if given {
+			return n - 1, true
+		}
+		return math.MaxInt, true
+	}
+	return top, false
+}
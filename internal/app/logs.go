@@ -0,0 +1,467 @@
+// internal/ui/app/logs.go
+package app
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+	"github.com/HaPhanBaoMinh/kmet/internal/logs"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/styles"
+)
+
+// logLevel is the minimum severity shown in the logs pane.
+type logLevel int
+
+const (
+	levelAll logLevel = iota
+	levelWarn
+	levelError
+)
+
+func (l logLevel) String() string {
+	switch l {
+	case levelWarn:
+		return "WARN+"
+	case levelError:
+		return "ERROR"
+	default:
+		return "ALL"
+	}
+}
+
+func (l logLevel) allows(level string) bool {
+	switch l {
+	case levelWarn:
+		return logs.Rank(level) >= 2
+	case levelError:
+		return logs.Rank(level) >= 3
+	default:
+		return true
+	}
+}
+
+// logFilter hides lines that don't match. The expression is split on spaces:
+// "+term" must be present, "-term" must be absent, everything else is joined
+// back together and used as a (case-insensitive) regex.
+type logFilter struct {
+	expr    string
+	re      *regexp.Regexp
+	include []string
+	exclude []string
+}
+
+func parseLogFilter(expr string) (logFilter, error) {
+	f := logFilter{expr: strings.TrimSpace(expr)}
+	var rest []string
+	for _, tok := range strings.Fields(f.expr) {
+		switch {
+		case len(tok) > 1 && tok[0] == '+':
+			f.include = append(f.include, strings.ToLower(tok[1:]))
+		case len(tok) > 1 && tok[0] == '-':
+			f.exclude = append(f.exclude, strings.ToLower(tok[1:]))
+		default:
+			rest = append(rest, tok)
+		}
+	}
+	if len(rest) > 0 {
+		re, err := regexp.Compile("(?i)" + strings.Join(rest, " "))
+		if err != nil {
+			return logFilter{}, fmt.Errorf("filter: %w", err)
+		}
+		f.re = re
+	}
+	return f, nil
+}
+
+func (f logFilter) empty() bool { return f.expr == "" }
+
+func (f logFilter) match(ln domain.LogLine) bool {
+	if f.empty() {
+		return true
+	}
+	hay := ln.Text + " " + ln.Source
+	low := strings.ToLower(hay)
+	for _, t := range f.include {
+		if !strings.Contains(low, t) {
+			return false
+		}
+	}
+	for _, t := range f.exclude {
+		if strings.Contains(low, t) {
+			return false
+		}
+	}
+	return f.re == nil || f.re.MatchString(hay)
+}
+
+// compileSearch turns the search box into a regex; invalid patterns are
+// searched literally so half-typed brackets don't error out.
+func compileSearch(s string) *regexp.Regexp {
+	if s == "" {
+		return nil
+	}
+	re, err := regexp.Compile("(?i)" + s)
+	if err != nil {
+		re = regexp.MustCompile("(?i)" + regexp.QuoteMeta(s))
+	}
+	return re
+}
+
+// logRanges are the history presets cycled with "t" in the logs pane.
+var logRanges = []struct {
+	label string
+	apply func(*domain.LogsTarget)
+}{
+	{"tail 500", func(t *domain.LogsTarget) { t.TailLines = 500 }},
+	{"last 10m", func(t *domain.LogsTarget) { t.SinceSeconds = 600 }},
+	{"last 1h", func(t *domain.LogsTarget) { t.SinceSeconds = 3600 }},
+	{"tail 100", func(t *domain.LogsTarget) { t.TailLines = 100 }},
+	{"all", func(t *domain.LogsTarget) {}},
+}
+
+// updateLogsKeys handles keys while the logs pane has focus.
+func (m Model) updateLogsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	k := m.keys
+	switch {
+	case key.Matches(msg, k.Back):
+		m.closeLogs()
+		return m, m.relayout()
+	case key.Matches(msg, k.LogSearch):
+		return m, m.openPrompt(promptLogSearch, "/", m.logSearchText)
+	case key.Matches(msg, k.LogFilter):
+		return m, m.openPrompt(promptLogFilter, "filter: ", m.logFilter.expr)
+	case key.Matches(msg, k.LogNext):
+		m.jumpToMatch(m.logMatchIdx + 1)
+		return m, nil
+	case key.Matches(msg, k.LogPrev):
+		m.jumpToMatch(m.logMatchIdx - 1)
+		return m, nil
+	case key.Matches(msg, k.LogLevel):
+		m.logLevel = (m.logLevel + 1) % 3
+		m.renderLogs()
+		return m, nil
+	case key.Matches(msg, k.LogRange):
+		m.logRange = (m.logRange + 1) % len(logRanges)
+		return m, m.openLogs(m.logTarget)
+	case key.Matches(msg, k.LogPrevious):
+		m.logPrevious = !m.logPrevious
+		return m, m.openLogs(m.logTarget)
+	case key.Matches(msg, k.LogRaw):
+		m.logRaw = !m.logRaw
+		m.renderLogs()
+		return m, nil
+	case key.Matches(msg, k.LogExport, k.LogExportAll):
+		// export: what's on screen (level+filter applied), export-all: the whole buffer
+		m.exportRaw = key.Matches(msg, k.LogExportAll)
+		label := "export filtered to: "
+		if m.exportRaw {
+			label = "export all to: "
+		}
+		return m, m.openPrompt(promptLogExport, label, m.exportName())
+	}
+	m.moveLogs(msg)
+	return m, nil
+}
+
+// openLogs (re)starts the stream for t with the current range/previous
+// settings, replacing whatever was streaming before.
+func (m *Model) openLogs(t domain.LogsTarget) tea.Cmd {
+	m.closeLogs()
+	m.resetLogs()
+	m.logTarget = t
+
+	t.TailLines, t.SinceSeconds, t.SinceTime = 0, 0, time.Time{}
+	logRanges[m.logRange].apply(&t)
+	t.Previous = m.logPrevious
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.logsCancel = cancel
+	m.logsOpen = true
+	return tea.Batch(m.consumeLogs(ctx, t), m.relayout())
+}
+
+func (m *Model) resetLogs() {
+	m.logRing.Reset()
+	m.logView, m.logViewSeq = nil, nil
+	m.logCols = logColumns{}
+	m.logDropped = 0
+	m.logMatches = nil
+	m.logMatchIdx = 0
+	m.logTop, m.logFollow = 0, true
+	m.logsVP.SetContent("")
+}
+
+func (m *Model) closeLogs() {
+	if m.logsCancel != nil {
+		m.logsCancel()
+		m.logsCancel = nil
+	}
+	m.logsOpen = false
+	m.logCh = nil
+	m.closePrompt()
+}
+
+// visibleLog reports whether a buffered line passes the level and filter.
+func (m Model) visibleLog(ln domain.LogLine) bool {
+	return m.logLevel.allows(ln.Level) && m.logFilter.match(ln)
+}
+
+func formatLogLine(ln domain.LogLine) string {
+	return fmt.Sprintf("%s %-5s %s [%s]",
+		ln.Time.Format("15:04:05.000"), ln.Level, ln.Text, ln.Source)
+}
+
+// logColumns are the widths used to align structured lines; they only grow
+// so columns don't jitter while new lines stream in.
+type logColumns struct {
+	source, msg int
+}
+
+const maxMsgCol = 60
+
+func (c *logColumns) fit(ln domain.LogLine) {
+	c.source = max(c.source, min(len(ln.Source), 32))
+	if ln.Format != "" {
+		c.msg = max(c.msg, min(len(ln.Msg), maxMsgCol))
+	}
+}
+
+// formatStructured renders "time level source msg k=v ..." in columns.
+// Lines no parser understood fall back to their raw text in the msg column.
+func formatStructured(ln domain.LogLine, c logColumns) string {
+	msg := ln.Text
+	if ln.Format != "" {
+		msg = ln.Msg
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %-5s %-*s  ", ln.Time.Format("15:04:05.000"), ln.Level, c.source, truncate(ln.Source, c.source))
+	if len(ln.Fields) == 0 {
+		b.WriteString(msg)
+		return b.String()
+	}
+	fmt.Fprintf(&b, "%-*s", c.msg, msg)
+	for _, f := range ln.Fields {
+		b.WriteString("  ")
+		b.WriteString(f.Key + "=" + f.Value)
+	}
+	return b.String()
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	if n <= 1 {
+		return s[:n]
+	}
+	return s[:n-1] + "…"
+}
+
+// renderLine formats ln and highlights search hits; row is the line's index
+// in logView, used to tell the current match apart.
+func (m *Model) renderLine(ln domain.LogLine, row int) string {
+	var s string
+	if m.logRaw {
+		s = formatLogLine(ln)
+	} else {
+		s = formatStructured(ln, m.logCols)
+	}
+	if m.logSearch != nil && m.logSearch.MatchString(s) {
+		m.logMatches = append(m.logMatches, row)
+		st := styles.Match
+		if len(m.logMatches)-1 == m.logMatchIdx {
+			st = styles.MatchCurrent
+		}
+		s = m.logSearch.ReplaceAllStringFunc(s, func(x string) string { return st.Render(x) })
+	}
+	// the colored gutter identifies the source; it's kept out of s so
+	// search highlighting never runs over escape codes
+	return styles.Source(ln.Source).Render("▌") + s
+}
+
+// renderLogs re-applies level/filter/search to the whole buffer. Used when
+// the view settings change; streaming goes through appendLogs.
+func (m *Model) renderLogs() {
+	m.logMatches = m.logMatches[:0]
+	m.logView, m.logViewSeq = m.logView[:0], m.logViewSeq[:0]
+	m.logCols = logColumns{}
+	if !m.logRaw {
+		for i := 0; i < m.logRing.Len(); i++ {
+			m.logCols.fit(m.logRing.At(i))
+		}
+	}
+	first := m.logRing.FirstSeq()
+	for i := 0; i < m.logRing.Len(); i++ {
+		ln := m.logRing.At(i)
+		if !m.visibleLog(ln) {
+			continue
+		}
+		m.logView = append(m.logView, m.renderLine(ln, len(m.logView)))
+		m.logViewSeq = append(m.logViewSeq, first+uint64(i))
+	}
+	m.flushLogs()
+}
+
+// appendLogs pushes a batch into the ring and only renders the new lines;
+// rows for evicted lines are trimmed from the front. A full re-render is
+// only needed when a new line widens the aligned columns.
+func (m *Model) appendLogs(lines []domain.LogLine) {
+	for _, ln := range lines {
+		m.logDropped += ln.Dropped
+		m.logRing.Push(ln)
+	}
+
+	// drop rows whose lines were evicted
+	first := m.logRing.FirstSeq()
+	k := 0
+	for k < len(m.logViewSeq) && m.logViewSeq[k] < first {
+		k++
+	}
+	if k > 0 {
+		if !m.logFollow {
+			m.logTop = max(0, m.logTop-k) // keep the same rows in view
+		}
+		m.logView = append(m.logView[:0], m.logView[k:]...)
+		m.logViewSeq = append(m.logViewSeq[:0], m.logViewSeq[k:]...)
+		j := 0
+		for _, r := range m.logMatches {
+			if r >= k {
+				m.logMatches[j] = r - k
+				j++
+			}
+		}
+		m.logMatchIdx = max(0, m.logMatchIdx-(len(m.logMatches)-j))
+		m.logMatches = m.logMatches[:j]
+	}
+
+	// the batch may be larger than what survived in the ring
+	start := m.logRing.Len() - min(len(lines), m.logRing.Len())
+	if !m.logRaw {
+		before := m.logCols
+		for i := start; i < m.logRing.Len(); i++ {
+			m.logCols.fit(m.logRing.At(i))
+		}
+		if m.logCols != before {
+			m.renderLogs()
+			return
+		}
+	}
+	for i := start; i < m.logRing.Len(); i++ {
+		ln := m.logRing.At(i)
+		if !m.visibleLog(ln) {
+			continue
+		}
+		m.logView = append(m.logView, m.renderLine(ln, len(m.logView)))
+		m.logViewSeq = append(m.logViewSeq, first+uint64(i))
+	}
+	m.flushLogs()
+}
+
+// flushLogs hands the rows in view to the viewport, the newest ones when
+// following. Only those are joined, so a batch costs the pane's height, not
+// the buffer's.
+func (m *Model) flushLogs() {
+	last := max(0, len(m.logView)-m.logsVP.Height)
+	if m.logFollow {
+		m.logTop = last
+	}
+	m.logTop = clamp(m.logTop, 0, last)
+	end := min(len(m.logView), m.logTop+max(0, m.logsVP.Height))
+	m.logsVP.SetContent(strings.Join(m.logView[m.logTop:end], "\n"))
+}
+
+// setLogTop scrolls the logs pane to row top; scrolling to the end follows
+// new lines again.
+func (m *Model) setLogTop(top int) {
+	last := max(0, len(m.logView)-m.logsVP.Height)
+	m.logTop = clamp(top, 0, last)
+	m.logFollow = m.logTop == last
+	m.flushLogs()
+}
+
+func (m *Model) scrollLogs(n int) { m.setLogTop(m.logTop + n) }
+
+// moveLogs applies a motion to the logs pane.
+func (m *Model) moveLogs(msg tea.KeyMsg) bool {
+	top, ok := m.scrollTo(msg, m.logTop, m.logsVP.Height)
+	if ok {
+		m.setLogTop(top)
+	}
+	return ok
+}
+
+// exportLines returns the buffered lines, optionally only the visible ones.
+func (m Model) exportLines(all bool) []domain.LogLine {
+	out := make([]domain.LogLine, 0, m.logRing.Len())
+	for i := 0; i < m.logRing.Len(); i++ {
+		ln := m.logRing.At(i)
+		if all || m.visibleLog(ln) {
+			out = append(out, ln)
+		}
+	}
+	return out
+}
+
+// exportName suggests a file name; use a .ndjson extension for NDJSON.
+func (m Model) exportName() string {
+	return fmt.Sprintf("kmet-%s-%s.log", logs.SafeName(m.logTarget.Name), time.Now().Format("20060102-150405"))
+}
+
+// jumpToMatch moves the current match (wrapping around) and scrolls to it.
+func (m *Model) jumpToMatch(i int) {
+	if len(m.logMatches) == 0 {
+		m.logMatchIdx = 0
+		return
+	}
+	n := len(m.logMatches)
+	m.logMatchIdx = ((i % n) + n) % n
+	m.renderLogs()
+	m.setLogTop(m.logMatches[m.logMatchIdx] - m.logsVP.Height/2)
+}
+
+func (m Model) logsTitle() string {
+	t := m.logTarget
+	name := t.Name
+	if t.Kind == "Node" {
+		svc := t.Service
+		if svc == "" {
+			svc = nodeEvents
+		}
+		name = "Node/" + t.Name + " " + svc
+	} else if t.Kind != "Pod" {
+		name = t.Kind + "/" + t.Name
+	} else if t.Container != "" {
+		name += "/" + t.Container
+	}
+	parts := []string{"Logs: " + name, logRanges[m.logRange].label}
+	if m.logPrevious {
+		parts = append(parts, "previous")
+	}
+	if m.logRaw {
+		parts = append(parts, "raw")
+	}
+	if m.logDropped > 0 {
+		parts = append(parts, styles.Warn.Render(fmt.Sprintf("%d lines dropped", m.logDropped)))
+	}
+	if m.logLevel != levelAll {
+		parts = append(parts, "level "+m.logLevel.String())
+	}
+	if !m.logFilter.empty() {
+		parts = append(parts, "filter "+m.logFilter.expr)
+	}
+	if m.logSearch != nil {
+		cur := 0
+		if len(m.logMatches) > 0 {
+			cur = m.logMatchIdx + 1
+		}
+		parts = append(parts, fmt.Sprintf("search %q %d/%d", m.logSearchText, cur, len(m.logMatches)))
+	}
+	return strings.Join(parts, "  ")
+}
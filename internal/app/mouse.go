@@ -0,0 +1,220 @@
+// internal/ui/app/mouse.go
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/styles"
+)
+
+// Mouse: a click selects a row, a tab, a treemap tile or sorts by a column
+// header; the wheel scrolls whatever it is over; the top border of the info
+// or logs pane can be dragged. Positions are worked out from the heights
+// View lays out with.
+
+// paneBorder is the top border of a pane under the table.
+type paneBorder uint8
+
+const (
+	noBorder paneBorder = iota
+	infoBorder
+	logsBorder
+)
+
+// wheelLines is how far one wheel step scrolls a text pane.
+const wheelLines = 3
+
+// panes are the first rows of the table and of the info and logs boxes
+// (their top border), -1 for a closed pane.
+func (m Model) panes() (table, info, logs int) {
+	table = lipgloss.Height(styles.Header.Render("x"))
+	y := table + m.table.Height()
+	info, logs = -1, -1
+	if m.infoOpen {
+		info = y
+		y += m.infoLines() + 2
+	}
+	if m.logsOpen {
+		logs = y
+	}
+	return table, info, logs
+}
+
+func (m Model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.promptKind != promptNone {
+		return m, nil
+	}
+	if msg.Action == tea.MouseActionRelease {
+		m.drag = noBorder
+		return m, nil
+	}
+	if msg.Action == tea.MouseActionMotion {
+		if m.drag != noBorder {
+			m.dragBorder(msg.Y)
+		}
+		return m, nil
+	}
+	if m.helpMode != 0 {
+		m.helpMode = 0 // any click closes help, as any key does
+		return m, nil
+	}
+	wheel := 0
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		wheel = -1
+	case tea.MouseButtonWheelDown:
+		wheel = 1
+	case tea.MouseButtonLeft:
+	default:
+		return m, nil
+	}
+
+	switch {
+	case m.chooser.open():
+		m.chooser.cursor = clamp(m.chooser.cursor+wheel, 0, len(m.chooser.items)-1)
+	case m.picker.open():
+		if wheel < 0 {
+			m.picker.table.MoveUp(1)
+		} else if wheel > 0 {
+			m.picker.table.MoveDown(1)
+		}
+	case m.detail.open():
+		return m, m.detailMouse(msg, wheel)
+	case m.heat.on:
+		m.heat.row = max(0, m.heat.row+wheel)
+		m.heatChart() // clamps the cursor
+	case m.tree.on:
+		if wheel == 0 {
+			return m, m.treeClick(msg.X-1, msg.Y-3) // page padding; header, title and selection lines
+		}
+	case m.dash.on:
+		return m, m.dashMouse(msg, wheel)
+	default:
+		return m, m.mainMouse(msg, wheel)
+	}
+	return m, nil
+}
+
+func (m *Model) detailMouse(msg tea.MouseMsg, wheel int) tea.Cmd {
+	d := &m.detail
+	switch {
+	case wheel < 0:
+		d.vp.LineUp(wheelLines)
+	case wheel > 0:
+		d.vp.LineDown(wheelLines)
+	case msg.Y == lipgloss.Height(styles.Header.Render("x")): // the tab bar
+		if i := d.tabAt(msg.X); i >= 0 {
+			return m.gotoTab(i)
+		}
+	}
+	return nil
+}
+
+// treeClick selects the tile under (x, y) of the map; a click on the
+// selected tile zooms into it.
+func (m *Model) treeClick(x, y int) tea.Cmd {
+	tm, tiles, _ := m.treeChart()
+	for i, r := range tm.Layout() {
+		if x >= r.X && x < r.X+r.W && y >= r.Y && y < r.Y+r.H {
+			if i == tm.Sel {
+				return m.zoomIn()
+			}
+			m.tree.cur = tiles[i].key
+		}
+	}
+	return nil
+}
+
+// mainMouse handles the table and its panes.
+func (m *Model) mainMouse(msg tea.MouseMsg, wheel int) tea.Cmd {
+	top, info, logs := m.panes()
+	switch {
+	case msg.Y < top:
+		if wheel == 0 && m.onViewLabel(msg.X) {
+			return m.switchView()
+		}
+	case logs >= 0 && msg.Y > logs:
+		if wheel < 0 {
+			m.scrollLogs(-wheelLines)
+		} else if wheel > 0 {
+			m.scrollLogs(wheelLines)
+		}
+	case wheel != 0:
+		if msg.Y < top+m.table.Height() {
+			if wheel < 0 {
+				m.table.MoveUp(1)
+			} else {
+				m.table.MoveDown(1)
+			}
+		}
+	case msg.Y == info:
+		m.drag = infoBorder
+	case msg.Y == logs:
+		m.drag = logsBorder
+	case msg.Y == top:
+		if c, ok := m.table.ColumnAt(msg.X - 1); ok { // table padding
+			m.sortByColumn(c)
+		}
+	default:
+		if i, ok := m.table.RowAt(msg.Y - top); ok {
+			m.table.SetCursor(i)
+		}
+	}
+	return nil
+}
+
+// onViewLabel reports whether column x of the header is on "view: Pods".
+func (m Model) onViewLabel(x int) bool {
+	line := m.headerLine()
+	i := strings.Index(line, "view: ")
+	if i < 0 {
+		return false
+	}
+	at := ansi.StringWidth(line[:i])
+	end := at + ansi.StringWidth("view: "+map[View]string{ViewPods: "Pods", ViewNodes: "Nodes"}[m.view])
+	return x >= at && x < end
+}
+
+// sortByColumn sorts by the clicked column, flipping the direction when it
+// is already the sort column.
+func (m *Model) sortByColumn(c int) {
+	title := m.table.Columns()[c].Title
+	if strings.HasSuffix(title, " "+arrow(true)) || strings.HasSuffix(title, " "+arrow(false)) {
+		m.flipSort()
+		return
+	}
+	if key := m.sortForTitle(title); key != "" {
+		m.setSort(key)
+	}
+}
+
+// dragBorder moves the dragged pane border to row y. With logs open the
+// table and the logs share the rows; with just the info panel its charts
+// grow or shrink.
+func (m *Model) dragBorder(y int) {
+	top, _, _ := m.panes()
+	base := m.paneBase()
+	switch {
+	case m.logsOpen:
+		rest := base
+		if m.infoOpen {
+			rest = max(10, base-m.infoLines()-2)
+		}
+		h := y - top
+		if m.infoOpen && m.drag == logsBorder { // under the info panel
+			h -= m.infoLines() + 2
+		}
+		m.tableShare = float64(clamp(h, 3, rest-3)) / float64(rest)
+	case m.infoOpen:
+		h := max(5, y-top)
+		m.infoGrow += (base - h - 2) - m.infoLines()
+		m.infoGrow = max(m.infoGrow, 4-chartHeight)
+	default:
+		return
+	}
+	m.layout()
+}
@@ -0,0 +1,132 @@
+// internal/ui/app/picker.go
+package app
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/styles"
+)
+
+type pickerKind int
+
+const (
+	pickNone pickerKind = iota
+	pickNamespace
+	pickNodeLog
+)
+
+// picker is the centered single-column chooser overlay (namespaces, node
+// log services...).
+type picker struct {
+	kind  pickerKind
+	title string
+	items []string
+	table table.Model
+	node  string // node the node-log picker was opened for
+}
+
+func newPicker(kind pickerKind, title, header string, items []string, current string) picker {
+	t := table.New()
+	t.SetColumns([]table.Column{{Title: header, Width: 32}})
+	rows := make([]table.Row, 0, len(items))
+	cur := 0
+	for i, it := range items {
+		rows = append(rows, table.Row{it})
+		if it == current {
+			cur = i
+		}
+	}
+	t.SetRows(rows)
+	t.SetHeight(10)
+	t.SetWidth(36)
+	st := table.DefaultStyles()
+	st.Header = st.Header.BorderForeground(styles.Faint.GetForeground())
+	st.Selected = styles.Selected
+	t.SetStyles(st)
+	t.Focus()
+	t.SetCursor(cur)
+	return picker{kind: kind, title: title, items: items, table: t}
+}
+
+func (p picker) open() bool { return p.kind != pickNone }
+
+func (p picker) selected() string {
+	if len(p.items) == 0 {
+		return ""
+	}
+	return p.items[clamp(p.table.Cursor(), 0, len(p.items)-1)]
+}
+
+func (p picker) view(width, height int) string {
+	box := styles.Popup.Width(40).Height(14)
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		styles.Title.Render(" "+p.title+" "),
+		p.table.View(),
+	)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box.Render(content))
+}
+
+// updatePicker handles keys while a picker is open.
+func (m Model) updatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	k := m.keys
+	t := &m.picker.table
+	n, given := m.popCount()
+	switch {
+	case key.Matches(msg, k.Back):
+		m.picker = picker{}
+	case key.Matches(msg, k.Select):
+		p := m.picker
+		m.picker = picker{}
+		return m.pick(p)
+	case key.Matches(msg, k.Up):
+		t.MoveUp(n)
+	case key.Matches(msg, k.Down):
+		t.MoveDown(n)
+	case key.Matches(msg, k.PageUp):
+		t.MoveUp(n * t.Height())
+	case key.Matches(msg, k.PageDown):
+		t.MoveDown(n * t.Height())
+	case key.Matches(msg, k.Top):
+		t.GotoTop()
+	case key.Matches(msg, k.Bottom):
+		if given {
+			t.SetCursor(n - 1)
+		} else {
+			t.GotoBottom()
+		}
+	case key.Matches(msg, k.Quit):
+		m.closeLogs()
+		m.cancel()
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m Model) pick(p picker) (tea.Model, tea.Cmd) {
+	choice := p.selected()
+	if choice == "" {
+		return m, nil
+	}
+	switch p.kind {
+	case pickNamespace:
+		if choice != m.ns {
+			m.ns = choice
+			m.table.SetCursor(0)
+			m.infoOpen = false
+			m.closeLogs()
+			return m, m.fetch()
+		}
+	case pickNodeLog:
+		svc := choice
+		if svc == nodeEvents {
+			svc = ""
+		}
+		m.logPrevious = false
+		return m, m.openLogs(domain.LogsTarget{Kind: "Node", Name: p.node, Service: svc})
+	}
+	return m, nil
+}
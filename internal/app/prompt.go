@@ -0,0 +1,135 @@
+// internal/ui/app/prompt.go
+package app
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+	"github.com/HaPhanBaoMinh/kmet/internal/logs"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/styles"
+)
+
+// The footer doubles as a one-line prompt for searches, filters and paths.
+type promptKind int
+
+const (
+	promptNone promptKind = iota
+	promptLogSearch
+	promptLogFilter
+	promptLogSelector
+	promptLogExport
+	promptFind
+	promptLabelSelector
+	promptFieldSelector
+	promptCommand
+	promptDetailSearch
+)
+
+func newPrompt() textinput.Model {
+	ti := textinput.New()
+	ti.CharLimit = 256
+	return ti
+}
+
+func (m *Model) openPrompt(kind promptKind, label, value string) tea.Cmd {
+	m.promptKind = kind
+	m.prompt.Prompt = label
+	m.prompt.SetValue(value)
+	m.prompt.CursorEnd()
+	m.promptErr = ""
+	return m.prompt.Focus()
+}
+
+func (m *Model) closePrompt() {
+	m.promptKind = promptNone
+	m.prompt.Blur()
+}
+
+// updatePrompt feeds a key to the active prompt, applying it on Enter.
+func (m Model) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		if m.promptKind == promptFind {
+			m.setFind("")
+		}
+		m.closePrompt()
+		return m, nil
+	case "enter":
+		kind, val := m.promptKind, m.prompt.Value()
+		m.closePrompt()
+		switch kind {
+		case promptLogSearch:
+			m.logSearch = compileSearch(val)
+			m.logSearchText = val
+			m.renderLogs()
+			m.jumpToMatch(0)
+		case promptLogFilter:
+			f, err := parseLogFilter(val)
+			if err != nil {
+				cmd := m.openPrompt(kind, m.prompt.Prompt, val)
+				m.promptErr = err.Error()
+				return m, cmd
+			}
+			m.logFilter = f
+			m.renderLogs()
+		case promptLogExport:
+			if val == "" {
+				return m, nil
+			}
+			lines := m.exportLines(m.exportRaw)
+			if err := logs.Export(val, lines); err != nil {
+				m.status = styles.Danger.Render("export: " + err.Error())
+			} else {
+				m.status = fmt.Sprintf("wrote %d lines to %s", len(lines), val)
+			}
+		case promptDetailSearch:
+			m.detail.search = compileSearch(val)
+			m.detail.searchText = val
+			m.detail.matchIdx = 0
+			m.renderDetail()
+			m.jumpToDetailMatch(0)
+		case promptFind:
+			m.setFind(val)
+		case promptCommand:
+			cmd, err := m.runCommand(val)
+			if err != nil {
+				reopen := m.openPrompt(kind, m.prompt.Prompt, val)
+				m.promptErr = err.Error()
+				return m, reopen
+			}
+			return m, cmd
+		case promptLabelSelector, promptFieldSelector:
+			sel := m.selector
+			if kind == promptLabelSelector {
+				sel.Labels = val
+			} else {
+				sel.Fields = val
+			}
+			sel, err := ParseSelector(sel.Labels, sel.Fields)
+			if err != nil {
+				cmd := m.openPrompt(kind, m.prompt.Prompt, val)
+				m.promptErr = err.Error()
+				return m, cmd
+			}
+			m.selector = sel
+			m.autoCursor = true
+			return m, m.fetch()
+		case promptLogSelector:
+			if val == "" {
+				return m, nil
+			}
+			m.logPrevious = false
+			return m, m.openLogs(domain.LogsTarget{Namespace: m.ns, Kind: "Selector", Name: val})
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.prompt, cmd = m.prompt.Update(msg)
+	if m.promptKind == promptFind {
+		m.setFind(m.prompt.Value()) // narrow as you type
+	}
+	return m, cmd
+}
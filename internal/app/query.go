@@ -0,0 +1,221 @@
+// internal/ui/app/query.go
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/config"
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+	"github.com/HaPhanBaoMinh/kmet/internal/filter"
+)
+
+// Fields the filter language can use. CPU is in cores and memory in bytes,
+// so quantity literals compare directly: cpu > 200m, mem > 512Mi.
+type podField struct {
+	kind filter.Kind
+	get  func(m *Model, p *domain.PodMetric) any
+}
+
+var podFields = map[string]podField{
+	"name":      {filter.String, func(_ *Model, p *domain.PodMetric) any { return p.PodName }},
+	"pod":       {filter.String, func(_ *Model, p *domain.PodMetric) any { return p.PodName }},
+	"ns":        {filter.String, func(_ *Model, p *domain.PodMetric) any { return p.Namespace }},
+	"namespace": {filter.String, func(_ *Model, p *domain.PodMetric) any { return p.Namespace }},
+	"container": {filter.String, func(_ *Model, p *domain.PodMetric) any { return p.Container }},
+	"node":      {filter.String, func(_ *Model, p *domain.PodMetric) any { return p.NodeName }},
+	"phase":     {filter.String, func(_ *Model, p *domain.PodMetric) any { return p.Phase }},
+	"owner":     {filter.String, func(_ *Model, p *domain.PodMetric) any { return p.OwnerName }},
+	"kind":      {filter.String, func(_ *Model, p *domain.PodMetric) any { return p.OwnerKind }},
+	"ready":     {filter.Number, func(_ *Model, p *domain.PodMetric) any { return readyRatio(p.Ready) }},
+	"restarts":  {filter.Number, func(_ *Model, p *domain.PodMetric) any { return float64(p.Restarts) }},
+	"cpu":       {filter.Number, func(_ *Model, p *domain.PodMetric) any { return float64(p.CPUm) / 1000 }},
+	"mem":       {filter.Number, func(_ *Model, p *domain.PodMetric) any { return float64(p.MemBytes) }},
+	"cpu.req":   {filter.Number, func(_ *Model, p *domain.PodMetric) any { return float64(p.CPUReqm) / 1000 }},
+	"mem.req":   {filter.Number, func(_ *Model, p *domain.PodMetric) any { return float64(p.MemReqBytes) }},
+	"cpu.lim":   {filter.Number, func(_ *Model, p *domain.PodMetric) any { return float64(p.CPULimm) / 1000 }},
+	"mem.lim":   {filter.Number, func(_ *Model, p *domain.PodMetric) any { return float64(p.MemLimBytes) }},
+	"cpu/node":  {filter.Number, func(_ *Model, p *domain.PodMetric) any { return ratio(float64(p.CPUm), float64(p.NodeCPUm)) }},
+	"mem/node":  {filter.Number, func(_ *Model, p *domain.PodMetric) any { return ratio(float64(p.MemBytes), float64(p.NodeMemBytes)) }},
+	"qos":       {filter.String, func(_ *Model, p *domain.PodMetric) any { return p.QoS }},
+	"cpu/req":   {filter.Number, func(_ *Model, p *domain.PodMetric) any { return ratio(float64(p.CPUm), float64(p.CPUReqm)) }},
+	"mem/req":   {filter.Number, func(_ *Model, p *domain.PodMetric) any { return ratio(float64(p.MemBytes), float64(p.MemReqBytes)) }},
+	"err": {filter.Number, func(m *Model, p *domain.PodMetric) any {
+		if m.errRate == nil {
+			return 0.0
+		}
+		n, _, _ := m.errRate.Rate(podTarget(*p))
+		return float64(n)
+	}},
+}
+
+type nodeField struct {
+	kind filter.Kind
+	get  func(n *domain.NodeMetric) any
+}
+
+// Node CPU and memory are the used fraction of allocatable: cpu > 80%.
+var nodeFields = map[string]nodeField{
+	"name":    {filter.String, func(n *domain.NodeMetric) any { return n.NodeName }},
+	"node":    {filter.String, func(n *domain.NodeMetric) any { return n.NodeName }},
+	"version": {filter.String, func(n *domain.NodeMetric) any { return n.K8sVer }},
+	"cpu":     {filter.Number, func(n *domain.NodeMetric) any { return n.CPUUsed }},
+	"mem":     {filter.Number, func(n *domain.NodeMetric) any { return n.MEMUsed }},
+	"pods":    {filter.Number, func(n *domain.NodeMetric) any { return float64(n.Pods) }},
+}
+
+var podSchema, nodeSchema = func() (filter.Schema, filter.Schema) {
+	ps, ns := filter.Schema{}, filter.Schema{}
+	for k, f := range podFields {
+		ps[k] = f.kind
+	}
+	for k, f := range nodeFields {
+		ns[k] = f.kind
+	}
+	return ps, ns
+}()
+
+type podEnv struct {
+	m *Model
+	p *domain.PodMetric
+}
+
+func (e podEnv) Field(name string) any { return podFields[name].get(e.m, e.p) }
+
+type nodeEnv struct{ n *domain.NodeMetric }
+
+func (e nodeEnv) Field(name string) any { return nodeFields[name].get(e.n) }
+
+func ratio(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	return a / b
+}
+
+// readyRatio turns "2/3" into 0.67, so "ready < 1" finds pods not fully up.
+func readyRatio(s string) float64 {
+	a, b, ok := strings.Cut(s, "/")
+	if !ok {
+		return 0
+	}
+	x, _ := strconv.Atoi(a)
+	y, _ := strconv.Atoi(b)
+	return ratio(float64(x), float64(y))
+}
+
+// setFilter compiles src for both tables. It must compile for the current
+// view; the other view just goes unfiltered if it uses fields it lacks.
+func (m *Model) setFilter(src string) error {
+	src = strings.TrimSpace(src)
+	if src == "" {
+		m.exprText, m.podExpr, m.nodeExpr = "", nil, nil
+		m.applyFilters()
+		m.rebuildTable()
+		return nil
+	}
+	pe, perr := filter.Compile(src, podSchema)
+	ne, nerr := filter.Compile(src, nodeSchema)
+	if m.view == ViewPods && perr != nil {
+		return perr
+	}
+	if m.view == ViewNodes && nerr != nil {
+		return nerr
+	}
+	m.exprText, m.podExpr, m.nodeExpr = src, pe, ne
+	m.applyFilters()
+	m.rebuildTable()
+	return nil
+}
+
+// runCommand executes a ":" command line.
+func (m *Model) runCommand(line string) (tea.Cmd, error) {
+	name, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+	arg = strings.TrimSpace(arg)
+	switch name {
+	case "":
+		return nil, nil
+	case "filter", "f":
+		if strings.HasPrefix(arg, "@") {
+			expr, ok := m.cfg.Filters[arg[1:]]
+			if !ok {
+				return nil, fmt.Errorf("no saved filter %q (have: %s)", arg[1:], m.filterNames())
+			}
+			arg = expr
+		}
+		return nil, m.setFilter(arg)
+	case "save":
+		if arg == "" || strings.ContainsAny(arg, " @") {
+			return nil, fmt.Errorf("usage: save <name>")
+		}
+		if m.exprText == "" {
+			return nil, fmt.Errorf("no filter to save; set one with :filter <expr>")
+		}
+		if err := config.SaveFilter(m.cfgPath, arg, m.exprText); err != nil {
+			return nil, err
+		}
+		if m.cfg.Filters == nil {
+			m.cfg.Filters = map[string]string{}
+		}
+		m.cfg.Filters[arg] = m.exprText
+		m.status = fmt.Sprintf("saved filter @%s to %s", arg, m.cfgPath)
+		return nil, nil
+	case "filters":
+		m.status = "saved filters: " + m.filterNames()
+		return nil, nil
+	case "sort":
+		key, dir, _ := strings.Cut(arg, " ")
+		if !m.setSort(key) {
+			return nil, fmt.Errorf("can't sort by %q here (have: %s)", key, strings.Join(m.sortKeys(), ", "))
+		}
+		switch strings.TrimSpace(dir) {
+		case "asc":
+			m.curSort().desc = false
+		case "desc":
+			m.curSort().desc = true
+		case "":
+		default:
+			return nil, fmt.Errorf("usage: sort <key> [asc|desc]")
+		}
+		m.resort()
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown command %q (filter <expr>, filter @name, save <name>, filters, sort <key> [asc|desc])", name)
+}
+
+func (m *Model) filterNames() string {
+	if len(m.cfg.Filters) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(m.cfg.Filters))
+	for n := range m.cfg.Filters {
+		names = append(names, "@"+n)
+	}
+	sort.Strings(names)
+	return strings.Join(names, " ")
+}
+
+// filterStatus is the footer while a find or filter narrows the table.
+func (m Model) filterStatus() string {
+	shown, total, expr := len(m.pods), len(m.allPods), m.podExpr
+	if m.view == ViewNodes {
+		shown, total, expr = len(m.nodes), len(m.allNodes), m.nodeExpr
+	}
+	var parts []string
+	if m.find != "" {
+		parts = append(parts, fmt.Sprintf("find %q", m.find))
+	}
+	if m.exprText != "" {
+		if expr == nil {
+			parts = append(parts, fmt.Sprintf("filter %q (not for this view)", m.exprText))
+		} else {
+			parts = append(parts, "filter "+m.exprText)
+		}
+	}
+	parts = append(parts, fmt.Sprintf("%d of %d", shown, total), "[Esc] clear")
+	return strings.Join(parts, " • ")
+}
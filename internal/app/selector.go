@@ -0,0 +1,37 @@
+// internal/ui/app/selector.go
+package app
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+)
+
+// ParseSelector validates label and field selectors the way the API server
+// will and returns them in canonical form. Empty strings select everything.
+func ParseSelector(ls, fs string) (domain.Selector, error) {
+	l, err := labels.Parse(ls)
+	if err != nil {
+		return domain.Selector{}, fmt.Errorf("label selector: %w", err)
+	}
+	f, err := fields.ParseSelector(fs)
+	if err != nil {
+		return domain.Selector{}, fmt.Errorf("field selector: %w", err)
+	}
+	return domain.Selector{Labels: l.String(), Fields: f.String()}, nil
+}
+
+// selectorLabel is the header suffix describing the active selectors.
+func selectorLabel(s domain.Selector) string {
+	out := ""
+	if s.Labels != "" {
+		out += "  -l " + s.Labels
+	}
+	if s.Fields != "" {
+		out += "  --field-selector " + s.Fields
+	}
+	return out
+}
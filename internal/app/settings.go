@@ -0,0 +1,234 @@
+// internal/ui/app/settings.go
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/config"
+	"github.com/HaPhanBaoMinh/kmet/internal/logs"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/styles"
+)
+
+// defaultRefresh is how often metrics are fetched unless configured.
+const defaultRefresh = 2 * time.Second
+
+// defaultThresholds are where usage turns yellow and red, in percent of
+// the request, limit or node allocatable.
+var defaultThresholds = map[string]config.Level{
+	"cpu/req":  {Warn: 80, Crit: 100},
+	"mem/req":  {Warn: 80, Crit: 100},
+	"cpu/lim":  {Warn: 80, Crit: 95},
+	"mem/lim":  {Warn: 80, Crit: 95},
+	"cpu/node": {Warn: 25, Crit: 50},
+	"mem/node": {Warn: 25, Crit: 50},
+	"node.cpu": {Warn: 70, Crit: 90},
+	"node.mem": {Warn: 70, Crit: 90},
+}
+
+// applySettings sets the model up from the config for its kube context.
+func (m *Model) applySettings(s config.Settings) {
+	m.ns = config.Or(s.Namespace, "default")
+	if s.View == "nodes" {
+		m.view = ViewNodes
+	}
+	if k, dir, _ := strings.Cut(s.Sort.Pods, " "); k != "" {
+		if ps, ok := findPodSort(k); ok {
+			m.podSort = sortSpec{k, direction(dir, ps.desc)}
+		}
+	}
+	if k, dir, _ := strings.Cut(s.Sort.Nodes, " "); k != "" {
+		if ns, ok := findNodeSort(k); ok {
+			m.nodeSort = sortSpec{k, direction(dir, ns.desc)}
+		}
+	}
+	m.refresh = config.Or(s.Refresh, defaultRefresh)
+	m.podCols, m.nodeCols = s.Columns.Pods, s.Columns.Nodes
+	if len(m.podCols) == 0 {
+		m.podCols = columnsOf(defaultPodColumns)
+	}
+	if len(m.nodeCols) == 0 {
+		m.nodeCols = columnsOf(defaultNodeColumns)
+	}
+	m.thresholds = map[string]config.Level{}
+	for k, l := range defaultThresholds {
+		m.thresholds[k] = l
+	}
+	for k, l := range s.Thresholds {
+		m.thresholds[k] = l
+	}
+	logs.Default = logs.WithFields(s.LogFields)
+	m.dashLayout = s.Dashboard
+	if len(m.dashLayout.Rows) == 0 {
+		m.dashLayout = defaultDashboard
+	}
+	m.keys = newKeyMap(s.Keys)
+	_ = styles.Use(config.Or(s.Theme, "dark")) // checked by CheckConfig
+	m.table.Styles.Selected = styles.Selected
+}
+
+// paletteColor is one color a user palette can replace.
+type paletteColor struct {
+	name string
+	dst  *string
+	v    string
+}
+
+func paletteColors(t *styles.Theme, p config.Palette) []paletteColor {
+	return []paletteColor{
+		{"accent", &t.Accent, p.Accent}, {"muted", &t.Muted, p.Muted},
+		{"header", &t.Header, p.Header}, {"footer", &t.Footer, p.Footer},
+		{"border", &t.Border, p.Border}, {"selected", &t.Selected, p.Selected},
+		{"danger", &t.Danger, p.Danger}, {"warn", &t.Warn, p.Warn},
+		{"good", &t.Good, p.Good}, {"faint", &t.Faint, p.Faint},
+		{"bar", &t.Bar, p.Bar}, {"spark", &t.Spark, p.Spark},
+		{"match-fg", &t.MatchFG, p.MatchFG}, {"match-bg", &t.MatchBG, p.MatchBG},
+	}
+}
+
+// definePalettes makes the config's user palettes usable as themes.
+func definePalettes(ps map[string]config.Palette) {
+	for name, p := range ps {
+		t, _ := styles.Lookup(config.Or(p.Base, "dark"))
+		for _, c := range paletteColors(&t, p) {
+			*c.dst = config.Or(c.v, *c.dst)
+		}
+		if len(p.Sources) > 0 {
+			t.Sources = p.Sources
+		}
+		styles.Define(name, t)
+	}
+}
+
+func checkPalettes(ps map[string]config.Palette) error {
+	for _, name := range sortedNames(ps) {
+		p := ps[name]
+		if p.Base != "" && !contains(styles.Themes(), p.Base) {
+			return fmt.Errorf("themes.%s.base: unknown theme %q (have: %s)", name, p.Base, strings.Join(styles.Themes(), ", "))
+		}
+		var t styles.Theme
+		for _, c := range paletteColors(&t, p) {
+			if c.v != "" && !styles.ValidColor(c.v) {
+				return fmt.Errorf("themes.%s.%s: %q is not a color, want #RRGGBB or an ANSI number 0-255", name, c.name, c.v)
+			}
+		}
+		for i, c := range p.Sources {
+			if !styles.ValidColor(c) {
+				return fmt.Errorf("themes.%s.sources[%d]: %q is not a color, want #RRGGBB or an ANSI number 0-255", name, i, c)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckTheme reports whether name is a built-in theme or one of the
+// config's palettes.
+func CheckTheme(c config.Config, name string) error {
+	if _, ok := c.Themes[name]; ok || name == "" || contains(styles.Themes(), name) {
+		return nil
+	}
+	have := styles.Themes()
+	for n := range c.Themes {
+		if !contains(have, n) {
+			have = append(have, n)
+		}
+	}
+	sort.Strings(have)
+	return fmt.Errorf("unknown theme %q (have: %s)", name, strings.Join(have, ", "))
+}
+
+// direction reads "asc"/"desc", falling back to def.
+func direction(dir string, def bool) bool {
+	switch strings.TrimSpace(dir) {
+	case "asc":
+		return false
+	case "desc":
+		return true
+	}
+	return def
+}
+
+// CheckConfig reports settings that name things kmet doesn't have: columns,
+// sort keys, thresholds, actions, themes, colors and dashboard panes.
+func CheckConfig(c config.Config) error {
+	if err := checkPalettes(c.Themes); err != nil {
+		return err
+	}
+	return c.Each(func(prefix string, s config.Settings) error {
+		if err := checkColumns(prefix+"columns", s.Columns); err != nil {
+			return err
+		}
+		if err := checkSort(prefix+"sort.pods", s.Sort.Pods, podSortKeys()); err != nil {
+			return err
+		}
+		if err := checkSort(prefix+"sort.nodes", s.Sort.Nodes, nodeSortKeys()); err != nil {
+			return err
+		}
+		for name := range s.Thresholds {
+			if _, ok := defaultThresholds[name]; !ok {
+				return fmt.Errorf("%sthresholds: unknown metric %q (have: %s)", prefix, name, strings.Join(sortedNames(defaultThresholds), ", "))
+			}
+		}
+		if err := checkKeys(prefix+"keys", s.Keys); err != nil {
+			return err
+		}
+		if err := checkDashboard(prefix+"dashboard", s.Dashboard); err != nil {
+			return err
+		}
+		if err := CheckTheme(c, s.Theme); err != nil {
+			return fmt.Errorf("%stheme: %v", prefix, err)
+		}
+		return nil
+	})
+}
+
+func checkSort(path, s string, keys []string) error {
+	if s == "" {
+		return nil
+	}
+	key, dir, _ := strings.Cut(strings.TrimSpace(s), " ")
+	known := contains(keys, key)
+	if label, ok := strings.CutPrefix(key, labelPrefix); ok && label != "" {
+		known = contains(keys, labelPrefix+"KEY") // only pods have label columns
+	}
+	if !known {
+		return fmt.Errorf("%s: unknown sort key %q (have: %s)", path, key, strings.Join(keys, ", "))
+	}
+	if d := strings.TrimSpace(dir); d != "" && d != "asc" && d != "desc" {
+		return fmt.Errorf("%s: direction must be asc or desc, got %q", path, d)
+	}
+	return nil
+}
+
+func podSortKeys() []string {
+	var keys []string
+	for _, s := range podSorts {
+		keys = append(keys, s.key)
+	}
+	for _, s := range columnPodSorts() {
+		keys = append(keys, s.key)
+	}
+	return append(keys, labelPrefix+"KEY")
+}
+
+func nodeSortKeys() []string {
+	var keys []string
+	for _, s := range nodeSorts {
+		keys = append(keys, s.key)
+	}
+	return keys
+}
+
+func contains(list []string, s string) bool { return indexOf(list, s) >= 0 }
+
+// indexOf is where s is in list, or -1.
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
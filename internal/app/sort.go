@@ -0,0 +1,340 @@
+// internal/ui/app/sort.go
+package app
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+	"github.com/HaPhanBaoMinh/kmet/internal/filter"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/widgets"
+)
+
+// sortSpec is a view's sort column and direction.
+type sortSpec struct {
+	key  string
+	desc bool
+}
+
+// A sort key compares two rows ascending; column is the header it marks.
+// Numbers default to descending (biggest first), text to ascending.
+type podSort struct {
+	key, column string
+	desc        bool
+	cmp         func(m *Model, a, b *domain.PodMetric) int
+}
+
+type nodeSort struct {
+	key, column string
+	desc        bool
+	cmp         func(a, b *domain.NodeMetric) int
+}
+
+var podSorts = []podSort{
+	{"cpu", "CPU", true, func(_ *Model, a, b *domain.PodMetric) int { return cmp.Compare(a.CPUm, b.CPUm) }},
+	{"mem", "MEM", true, func(_ *Model, a, b *domain.PodMetric) int { return cmp.Compare(a.MemBytes, b.MemBytes) }},
+	{"cpu/req", "CPU", true, func(_ *Model, a, b *domain.PodMetric) int {
+		return cmp.Compare(ratio(float64(a.CPUm), float64(a.CPUReqm)), ratio(float64(b.CPUm), float64(b.CPUReqm)))
+	}},
+	{"mem/req", "MEM", true, func(_ *Model, a, b *domain.PodMetric) int {
+		return cmp.Compare(ratio(float64(a.MemBytes), float64(a.MemReqBytes)), ratio(float64(b.MemBytes), float64(b.MemReqBytes)))
+	}},
+	{"err", "ERR/min", true, func(m *Model, a, b *domain.PodMetric) int {
+		return cmp.Compare(podFields["err"].get(m, a).(float64), podFields["err"].get(m, b).(float64))
+	}},
+	{"restarts", "RESTARTS", true, func(_ *Model, a, b *domain.PodMetric) int { return cmp.Compare(a.Restarts, b.Restarts) }},
+	{"ready", "READY", false, func(_ *Model, a, b *domain.PodMetric) int {
+		return cmp.Compare(readyRatio(a.Ready), readyRatio(b.Ready))
+	}},
+	{"age", "AGE", false, func(_ *Model, a, b *domain.PodMetric) int { return compareCreated(a.Created, b.Created) }},
+	{"name", "POD (ctr)", false, func(_ *Model, a, b *domain.PodMetric) int { return strings.Compare(a.PodName, b.PodName) }},
+	{"namespace", "NAMESPACE", false, func(_ *Model, a, b *domain.PodMetric) int { return strings.Compare(a.Namespace, b.Namespace) }},
+	{"node", "NODE", false, func(_ *Model, a, b *domain.PodMetric) int { return strings.Compare(a.NodeName, b.NodeName) }},
+	{"trend", "Trend", true, func(_ *Model, a, b *domain.PodMetric) int {
+		return cmp.Compare(latest(a.CPUTrend), latest(b.CPUTrend))
+	}},
+	{"mem.trend", "MEM trend", true, func(_ *Model, a, b *domain.PodMetric) int {
+		return cmp.Compare(latest(a.MemTrend), latest(b.MemTrend))
+	}},
+}
+
+var nodeSorts = []nodeSort{
+	{"cpu", "CPU%", true, func(a, b *domain.NodeMetric) int { return cmp.Compare(a.CPUUsed, b.CPUUsed) }},
+	{"mem", "MEM%", true, func(a, b *domain.NodeMetric) int { return cmp.Compare(a.MEMUsed, b.MEMUsed) }},
+	{"pods", "PODS", true, func(a, b *domain.NodeMetric) int { return cmp.Compare(a.Pods, b.Pods) }},
+	{"name", "NODE", false, func(a, b *domain.NodeMetric) int { return strings.Compare(a.NodeName, b.NodeName) }},
+	{"version", "K8S", false, func(a, b *domain.NodeMetric) int { return strings.Compare(a.K8sVer, b.K8sVer) }},
+	{"cpu.req", "CPU REQ", true, func(a, b *domain.NodeMetric) int {
+		return cmp.Compare(ratio(float64(a.CPUReqm), float64(a.CPUAllocm)), ratio(float64(b.CPUReqm), float64(b.CPUAllocm)))
+	}},
+	{"mem.req", "MEM REQ", true, func(a, b *domain.NodeMetric) int {
+		return cmp.Compare(ratio(float64(a.MemReqBytes), float64(a.MemAllocBytes)), ratio(float64(b.MemReqBytes), float64(b.MemAllocBytes)))
+	}},
+	{"trend", "Trend", true, func(a, b *domain.NodeMetric) int { return cmp.Compare(latest(a.CPUTrend), latest(b.CPUTrend)) }},
+	{"mem.trend", "MEM trend", true, func(a, b *domain.NodeMetric) int { return cmp.Compare(latest(a.MEMTrend), latest(b.MEMTrend)) }},
+}
+
+// columnPodSort is the sort of a pod column the list above doesn't cover:
+// a label column, or a column with a filter field of the same name, which
+// sorts on that field. Columns without a title (the bars) don't sort.
+func columnPodSort(name string) (podSort, bool) {
+	col, ok := podColumnFor(name)
+	if !ok || col.title == "" {
+		return podSort{}, false
+	}
+	if key, ok := strings.CutPrefix(name, labelPrefix); ok {
+		return podSort{name, col.title, false, func(_ *Model, a, b *domain.PodMetric) int {
+			return strings.Compare(a.Labels[key], b.Labels[key])
+		}}, true
+	}
+	f, ok := podFields[name]
+	if !ok {
+		return podSort{}, false
+	}
+	return podSort{name, col.title, f.kind == filter.Number, func(m *Model, a, b *domain.PodMetric) int {
+		if f.kind == filter.Number {
+			return cmp.Compare(f.get(m, a).(float64), f.get(m, b).(float64))
+		}
+		return strings.Compare(f.get(m, a).(string), f.get(m, b).(string))
+	}}, true
+}
+
+// columnPodSorts are the column sorts for the registry's columns whose
+// header no sort in podSorts already marks (POD sorts by name, not pod).
+func columnPodSorts() []podSort {
+	var out []podSort
+	for _, name := range sortedNames(podColumns) {
+		s, ok := columnPodSort(name)
+		if ok && !slices.ContainsFunc(podSorts, func(p podSort) bool { return p.column == s.column }) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// latest is a trend's newest sample, 0 without any.
+func latest(t domain.Trend) float64 {
+	if len(t.Samples) == 0 {
+		return 0
+	}
+	return t.Samples[len(t.Samples)-1]
+}
+
+// compareCreated orders creation times oldest first; unknown ones sort as
+// the newest. The times themselves are compared, not ages taken a moment
+// apart, so equal pods tie and fall through to the name.
+func compareCreated(a, b time.Time) int {
+	switch {
+	case a.IsZero() && !b.IsZero():
+		return 1
+	case b.IsZero() && !a.IsZero():
+		return -1
+	}
+	return a.Compare(b)
+}
+
+func findPodSort(key string) (podSort, bool) {
+	for _, s := range podSorts {
+		if s.key == key {
+			return s, true
+		}
+	}
+	if strings.HasPrefix(key, labelPrefix) {
+		return columnPodSort(key)
+	}
+	for _, s := range columnPodSorts() {
+		if s.key == key {
+			return s, true
+		}
+	}
+	return podSort{}, false
+}
+
+func findNodeSort(key string) (nodeSort, bool) {
+	for _, s := range nodeSorts {
+		if s.key == key {
+			return s, true
+		}
+	}
+	return nodeSort{}, false
+}
+
+// sortPods orders p by spec, breaking ties by namespace/name so rows with
+// equal values keep their place from one tick to the next.
+func (m *Model) sortPods(p []domain.PodMetric) {
+	s, ok := findPodSort(m.podSort.key)
+	if !ok {
+		s, _ = findPodSort("name")
+	}
+	sort.SliceStable(p, func(i, j int) bool {
+		c := s.cmp(m, &p[i], &p[j])
+		if m.podSort.desc {
+			c = -c
+		}
+		if c != 0 {
+			return c < 0
+		}
+		if p[i].Namespace != p[j].Namespace {
+			return p[i].Namespace < p[j].Namespace
+		}
+		return p[i].PodName < p[j].PodName
+	})
+}
+
+func (m *Model) sortNodes(n []domain.NodeMetric) {
+	s, ok := findNodeSort(m.nodeSort.key)
+	if !ok {
+		s, _ = findNodeSort("name")
+	}
+	sort.SliceStable(n, func(i, j int) bool {
+		c := s.cmp(&n[i], &n[j])
+		if m.nodeSort.desc {
+			c = -c
+		}
+		if c != 0 {
+			return c < 0
+		}
+		return n[i].NodeName < n[j].NodeName
+	})
+}
+
+// sortKeys lists the keys "s" cycles through in the current view.
+func (m *Model) sortKeys() []string {
+	var keys []string
+	if m.view == ViewNodes {
+		for _, s := range nodeSorts {
+			keys = append(keys, s.key)
+		}
+		return keys
+	}
+	for _, s := range podSorts {
+		if s.key == "err" && m.errRate == nil {
+			continue
+		}
+		keys = append(keys, s.key)
+	}
+	// then the other columns in view, labels included
+	for _, c := range m.podCols {
+		if s, ok := findPodSort(c.Name); ok && !slices.Contains(keys, s.key) && s.key != "err" {
+			keys = append(keys, s.key)
+		}
+	}
+	return keys
+}
+
+// sortForTitle is the sort key of the column titled title, the first of
+// its keys (CPU sorts by cpu, not cpu/req); "" for columns that don't sort.
+func (m *Model) sortForTitle(title string) string {
+	if m.view == ViewNodes {
+		for _, s := range nodeSorts {
+			if s.column == title {
+				return s.key
+			}
+		}
+		return ""
+	}
+	for _, s := range podSorts {
+		if s.column == title {
+			return s.key
+		}
+	}
+	for _, c := range m.podCols {
+		if s, ok := findPodSort(c.Name); ok && s.column == title {
+			return s.key
+		}
+	}
+	return ""
+}
+
+// curSort is the current view's sort spec.
+func (m *Model) curSort() *sortSpec {
+	if m.view == ViewNodes {
+		return &m.nodeSort
+	}
+	return &m.podSort
+}
+
+// setSort switches the current view to key in its default direction.
+func (m *Model) setSort(key string) bool {
+	var desc bool
+	if m.view == ViewNodes {
+		s, ok := findNodeSort(key)
+		if !ok {
+			return false
+		}
+		desc = s.desc
+	} else {
+		s, ok := findPodSort(key)
+		if !ok {
+			return false
+		}
+		desc = s.desc
+	}
+	*m.curSort() = sortSpec{key: key, desc: desc}
+	m.resort()
+	return true
+}
+
+// cycleSort moves to the next sort key of the current view.
+func (m *Model) cycleSort() {
+	keys := m.sortKeys()
+	cur := m.curSort().key
+	next := keys[0]
+	for i, k := range keys {
+		if k == cur {
+			next = keys[(i+1)%len(keys)]
+		}
+	}
+	m.setSort(next)
+}
+
+func (m *Model) flipSort() {
+	s := m.curSort()
+	s.desc = !s.desc
+	m.resort()
+}
+
+// resort reorders the cached rows without waiting for the next fetch.
+func (m *Model) resort() {
+	m.sortPods(m.allPods)
+	m.sortNodes(m.allNodes)
+	m.applyFilters()
+	m.rebuildTable()
+}
+
+// sortLabel is the header's "sort:" value.
+func (m Model) sortLabel() string {
+	s := m.curSort()
+	return s.key + " " + arrow(s.desc)
+}
+
+func arrow(desc bool) string {
+	if desc {
+		return "↓"
+	}
+	return "↑"
+}
+
+// markSorted puts the direction arrow on the sorted column's header.
+func (m Model) markSorted(cols []widgets.Column) {
+	var title string
+	desc := m.curSort().desc
+	if m.view == ViewNodes {
+		s, _ := findNodeSort(m.nodeSort.key)
+		title = s.column
+	} else {
+		s, _ := findPodSort(m.podSort.key)
+		title = s.column
+	}
+	for i := range cols {
+		if cols[i].Title == title && title != "" {
+			// narrow columns give up title characters, not the arrow
+			keep := max(1, min(len([]rune(title)), cols[i].Width-2))
+			cols[i].Title = string([]rune(title)[:keep]) + " " + arrow(desc)
+			return
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// internal/ui/app/thresholds.go
+package app
+
+import (
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/styles"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/widgets"
+)
+
+// severity is where a usage ratio falls against its thresholds.
+type severity int
+
+const (
+	sevNone severity = iota // nothing to compare with: no request, limit...
+	sevGood
+	sevWarn
+	sevCrit
+)
+
+// severity rates used/of against the metric's thresholds (m.thresholds).
+func (m *Model) severity(metric string, used, of float64) severity {
+	if of <= 0 {
+		return sevNone
+	}
+	l, pct := m.thresholds[metric], used/of*100
+	switch {
+	case pct >= l.Crit:
+		return sevCrit
+	case pct >= l.Warn:
+		return sevWarn
+	}
+	return sevGood
+}
+
+func worst(ss ...severity) severity {
+	w := sevNone
+	for _, s := range ss {
+		if s > w {
+			w = s
+		}
+	}
+	return w
+}
+
+// style is the severity's color, or def when there is none.
+func (s severity) style(def lipgloss.Style) lipgloss.Style {
+	switch s {
+	case sevGood:
+		return styles.Good
+	case sevWarn:
+		return styles.Warn
+	case sevCrit:
+		return styles.Danger
+	}
+	return def
+}
+
+// paint colors a number; bar draws a usage bar in the severity's color.
+func (s severity) paint(text string) string {
+	if s == sevNone {
+		return text
+	}
+	return s.style(lipgloss.NewStyle()).Render(text)
+}
+
+func (s severity) bar(v float64, w int) string {
+	return s.style(styles.Bar).Render(widgets.Bar(v, w))
+}
+
+// podCPU and podMem are the worst of usage against the pod's request, its
+// limit and its node's allocatable.
+func (m *Model) podCPU(p *domain.PodMetric) severity {
+	used := float64(p.CPUm)
+	return worst(
+		m.severity("cpu/req", used, float64(p.CPUReqm)),
+		m.severity("cpu/lim", used, float64(p.CPULimm)),
+		m.severity("cpu/node", used, float64(p.NodeCPUm)),
+	)
+}
+
+func (m *Model) podMem(p *domain.PodMetric) severity {
+	used := float64(p.MemBytes)
+	return worst(
+		m.severity("mem/req", used, float64(p.MemReqBytes)),
+		m.severity("mem/lim", used, float64(p.MemLimBytes)),
+		m.severity("mem/node", used, float64(p.NodeMemBytes)),
+	)
+}
+
+// podTints tints pods that are not Ready red and pods on a node under
+// pressure yellow. Completed pods are not "not Ready".
+func (m *Model) podTints() map[int]lipgloss.Style {
+	tints := map[int]lipgloss.Style{}
+	for i, p := range m.pods {
+		switch {
+		case p.Phase != "Succeeded" && (p.Phase != "Running" || readyRatio(p.Ready) < 1):
+			tints[i] = styles.Danger
+		case len(p.NodePressure) > 0:
+			tints[i] = styles.Warn
+		}
+	}
+	return tints
+}
+
+// nodeTints does the same for nodes, and for the pods of open nodes as
+// podTints would.
+func (m *Model) nodeTints() map[int]lipgloss.Style {
+	tints := map[int]lipgloss.Style{}
+	for i, r := range m.nodeRows {
+		n := m.nodes[r.node]
+		if r.pod >= 0 {
+			if p := m.nodePods[n.NodeName][r.pod]; p.Phase != "Succeeded" && (p.Phase != "Running" || readyRatio(p.Ready) < 1) {
+				tints[i] = styles.Danger
+			}
+			continue
+		}
+		switch {
+		case n.NotReady:
+			tints[i] = styles.Danger
+		case len(n.Pressure) > 0:
+			tints[i] = styles.Warn
+		}
+	}
+	return tints
+}
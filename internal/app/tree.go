@@ -0,0 +1,166 @@
+// internal/ui/app/tree.go
+package app
+
+import (
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/config"
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/styles"
+)
+
+// In the Nodes view a node expands into the pods scheduled on it, listed
+// under its row by CPU. nodeRows maps table rows back to nodes and pods,
+// since rows and m.nodes no longer line up once a node is open.
+
+// nodeRef is what a Nodes table row shows: a node, or one of its pods when
+// pod >= 0 (an index into m.nodePods of the node).
+type nodeRef struct {
+	node, pod int
+}
+
+type nodePodsMsg struct {
+	node string
+	pods []domain.PodMetric
+	err  error
+}
+
+// nodePodCells are the Nodes columns as shown on a pod row: usage as a
+// share of the node's allocatable, and against the pod's own requests in
+// the REQ columns. Columns not listed stay blank.
+var nodePodCells = map[string]func(m *Model, n *domain.NodeMetric, p *domain.PodMetric, w int) string{
+	"name": func(m *Model, n *domain.NodeMetric, p *domain.PodMetric, _ int) string {
+		return highlight(p.Namespace+"/"+p.PodName, m.find)
+	},
+	"cpu": func(m *Model, n *domain.NodeMetric, p *domain.PodMetric, _ int) string {
+		of := float64(config.Or(n.CPUAllocm, p.NodeCPUm))
+		return m.severity("cpu/node", float64(p.CPUm), of).paint(percent(float64(p.CPUm), of))
+	},
+	"cpu.bar": func(m *Model, n *domain.NodeMetric, p *domain.PodMetric, w int) string {
+		of := float64(config.Or(n.CPUAllocm, p.NodeCPUm))
+		return m.severity("cpu/node", float64(p.CPUm), of).bar(ratio(float64(p.CPUm), of), w-1)
+	},
+	"mem": func(m *Model, n *domain.NodeMetric, p *domain.PodMetric, _ int) string {
+		of := float64(config.Or(n.MemAllocBytes, p.NodeMemBytes))
+		return m.severity("mem/node", float64(p.MemBytes), of).paint(percent(float64(p.MemBytes), of))
+	},
+	"mem.bar": func(m *Model, n *domain.NodeMetric, p *domain.PodMetric, w int) string {
+		of := float64(config.Or(n.MemAllocBytes, p.NodeMemBytes))
+		return m.severity("mem/node", float64(p.MemBytes), of).bar(ratio(float64(p.MemBytes), of), w-1)
+	},
+	"cpu.req": func(m *Model, _ *domain.NodeMetric, p *domain.PodMetric, _ int) string {
+		return m.severity("cpu/req", float64(p.CPUm), float64(p.CPUReqm)).paint(percent(float64(p.CPUm), float64(p.CPUReqm)))
+	},
+	"mem.req": func(m *Model, _ *domain.NodeMetric, p *domain.PodMetric, _ int) string {
+		return m.severity("mem/req", float64(p.MemBytes), float64(p.MemReqBytes)).paint(percent(float64(p.MemBytes), float64(p.MemReqBytes)))
+	},
+	"pods": func(_ *Model, _ *domain.NodeMetric, p *domain.PodMetric, _ int) string { return p.Ready },
+	"trend": func(_ *Model, _ *domain.NodeMetric, p *domain.PodMetric, w int) string {
+		return spark(p.CPUTrend.Samples, w)
+	},
+	"mem.trend": func(_ *Model, _ *domain.NodeMetric, p *domain.PodMetric, w int) string {
+		return spark(p.MemTrend.Samples, w)
+	},
+}
+
+// selectedRef is the node (and pod) under the cursor; ok is false when the
+// table is empty.
+func (m Model) selectedRef() (ref nodeRef, ok bool) {
+	i := m.currentSelection()
+	if len(m.nodeRows) == 0 {
+		return nodeRef{}, false
+	}
+	return m.nodeRows[i%len(m.nodeRows)], true
+}
+
+// selectedNode is the node under the cursor, or the node of the pod under
+// it.
+func (m Model) selectedNode() (domain.NodeMetric, bool) {
+	ref, ok := m.selectedRef()
+	if !ok {
+		return domain.NodeMetric{}, false
+	}
+	return m.nodes[ref.node], true
+}
+
+// selectedNodePod is the pod under the cursor, if it is a pod row.
+func (m Model) selectedNodePod() (domain.PodMetric, bool) {
+	ref, ok := m.selectedRef()
+	if !ok || ref.pod < 0 {
+		return domain.PodMetric{}, false
+	}
+	return m.nodePods[m.nodes[ref.node].NodeName][ref.pod], true
+}
+
+// toggleNode opens or closes the node under the cursor; on a pod row it
+// closes the pod's node and moves up to it.
+func (m *Model) toggleNode() tea.Cmd {
+	ref, ok := m.selectedRef()
+	if !ok {
+		return nil
+	}
+	name := m.nodes[ref.node].NodeName
+	if m.expanded[name] {
+		delete(m.expanded, name)
+		delete(m.nodePods, name)
+		m.rebuildTable()
+		for i, r := range m.nodeRows {
+			if r.node == ref.node && r.pod < 0 {
+				m.table.SetCursor(i)
+			}
+		}
+		return nil
+	}
+	if m.expanded == nil {
+		m.expanded = map[string]bool{}
+	}
+	m.expanded[name] = true
+	m.rebuildTable()
+	return m.fetchNodePods(name)
+}
+
+func (m Model) fetchNodePods(node string) tea.Cmd {
+	return func() tea.Msg {
+		pods, err := m.repoM.ListPods(m.ctx, "all", domain.Selector{Fields: "spec.nodeName=" + node})
+		return nodePodsMsg{node, pods, err}
+	}
+}
+
+// fetchExpanded refreshes the pods of every open node.
+func (m Model) fetchExpanded() tea.Cmd {
+	if m.view != ViewNodes {
+		return nil
+	}
+	var cmds []tea.Cmd
+	for _, n := range sortedNames(m.expanded) {
+		cmds = append(cmds, m.fetchNodePods(n))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) setNodePods(msg nodePodsMsg) {
+	if !m.expanded[msg.node] {
+		return
+	}
+	if msg.err != nil {
+		m.status = styles.Danger.Render("pods of " + msg.node + ": " + msg.err.Error())
+		return
+	}
+	sort.SliceStable(msg.pods, func(i, j int) bool { return msg.pods[i].CPUm > msg.pods[j].CPUm })
+	if m.nodePods == nil {
+		m.nodePods = map[string][]domain.PodMetric{}
+	}
+	m.nodePods[msg.node] = msg.pods
+	m.rebuildTable()
+}
+
+// nodePodName is a pod row's first cell: the branch, then the pod.
+func nodePodName(cell string, last bool) string {
+	branch := "├─ "
+	if last {
+		branch = "└─ "
+	}
+	return styles.Faint.Render(branch) + cell
+}
@@ -0,0 +1,281 @@
+// internal/ui/app/treemap.go
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/styles"
+	"github.com/HaPhanBaoMinh/kmet/internal/ui/widgets"
+)
+
+// treemap is the full-screen map of where CPU or memory goes: a tile per
+// namespace, zoomed into its workloads, then their pods. Pods of every
+// namespace are fetched on open and on every tick while it is shown.
+type treemap struct {
+	on  bool
+	mem bool // MEM instead of CPU
+	req bool // sized by requests instead of usage
+
+	path []string // keys zoomed into: a namespace, then a workload
+	cur  string   // key of the selected tile, kept across refreshes
+
+	loaded bool
+	err    error
+	pods   []domain.PodMetric
+}
+
+type treemapMsg struct {
+	pods []domain.PodMetric
+	err  error
+}
+
+// treeLevels name what the tiles are at each zoom.
+var treeLevels = []string{"namespace", "workload", "pod"}
+
+// treeTile is one tile: the pods under a key, summed.
+type treeTile struct {
+	key, label string
+	ns         string // for the color: a namespace keeps its color zoomed in
+	value      float64
+	pods       []domain.PodMetric
+}
+
+func (m *Model) openTreemap() tea.Cmd {
+	m.tree.on, m.tree.loaded = true, false
+	m.tree.path, m.tree.cur = nil, ""
+	return m.fetchTreemap()
+}
+
+func (m Model) fetchTreemap() tea.Cmd {
+	return func() tea.Msg {
+		p, err := m.repoM.ListPods(m.ctx, "all", domain.Selector{})
+		return treemapMsg{p, err}
+	}
+}
+
+func (m *Model) setTreemap(msg treemapMsg) {
+	t := &m.tree
+	if !t.on {
+		return
+	}
+	t.loaded, t.err = true, msg.err
+	if msg.err == nil {
+		t.pods = msg.pods
+	}
+}
+
+// workloadOf is the key and label of a pod's workload; a bare pod is its own.
+func workloadOf(p domain.PodMetric) (string, string) {
+	if p.OwnerKind == "" {
+		return "Pod/" + p.PodName, p.PodName
+	}
+	return p.OwnerKind + "/" + p.OwnerName, p.OwnerName
+}
+
+// size is what a pod's tile is sized by.
+func (t treemap) size(p domain.PodMetric) float64 {
+	switch {
+	case t.mem && t.req:
+		return float64(p.MemReqBytes)
+	case t.mem:
+		return float64(p.MemBytes)
+	case t.req:
+		return float64(p.CPUReqm)
+	}
+	return float64(p.CPUm)
+}
+
+func (t treemap) amount(v float64) string {
+	if t.mem {
+		return bytesIEC(v)
+	}
+	return cores(v)
+}
+
+// tiles are the tiles at the current zoom, largest first, and how many
+// had nothing to show.
+func (t treemap) tiles() (tiles []treeTile, empty int) {
+	byKey := map[string]*treeTile{}
+	var keys []string
+	for _, p := range t.pods {
+		wk, wl := workloadOf(p)
+		k, label := p.Namespace, p.Namespace
+		switch {
+		case len(t.path) >= 1 && p.Namespace != t.path[0]:
+			continue
+		case len(t.path) >= 2 && wk != t.path[1]:
+			continue
+		case len(t.path) == 1:
+			k, label = wk, wl
+		case len(t.path) == 2:
+			k, label = p.PodName, p.PodName
+		}
+		tt, ok := byKey[k]
+		if !ok {
+			tt = &treeTile{key: k, label: label, ns: p.Namespace}
+			byKey[k] = tt
+			keys = append(keys, k)
+		}
+		tt.value += t.size(p)
+		tt.pods = append(tt.pods, p)
+	}
+	for _, k := range keys {
+		if byKey[k].value > 0 {
+			tiles = append(tiles, *byKey[k])
+		} else {
+			empty++
+		}
+	}
+	sort.SliceStable(tiles, func(i, j int) bool {
+		if tiles[i].value != tiles[j].value {
+			return tiles[i].value > tiles[j].value
+		}
+		return tiles[i].key < tiles[j].key
+	})
+	return tiles, empty
+}
+
+// treemapHeight is what the map gets: all but the header, the title, the
+// selected tile's line and the footer.
+func (m Model) treemapHeight() int { return max(4, m.height-4) }
+
+// treeChart is the map as shown, the selection resolved to an index.
+func (m Model) treeChart() (widgets.Treemap, []treeTile, int) {
+	t := m.tree
+	tiles, empty := t.tiles()
+	total := 0.0
+	for _, tt := range tiles {
+		total += tt.value
+	}
+	tm := widgets.Treemap{Width: m.width - 2, Height: m.treemapHeight(), Cursor: styles.Selected}
+	for i, tt := range tiles {
+		if tt.key == t.cur {
+			tm.Sel = i
+		}
+		lines := []string{fmt.Sprintf("%s  %s", t.amount(tt.value), share(ratio(tt.value, total)))}
+		if len(t.path) < 2 {
+			lines = append(lines, plural(len(tt.pods), "pod"))
+		}
+		tm.Tiles = append(tm.Tiles, widgets.Tile{Label: tt.label, Lines: lines, Value: tt.value, Color: styles.Source(tt.ns)})
+	}
+	return tm, tiles, empty
+}
+
+// zoomIn opens the selected tile; a pod opens its detail page.
+func (m *Model) zoomIn() tea.Cmd {
+	t := &m.tree
+	tm, tiles, _ := m.treeChart()
+	if len(tiles) == 0 {
+		return nil
+	}
+	sel := tiles[tm.Sel]
+	if len(t.path) < 2 {
+		t.path, t.cur = append(t.path, sel.key), ""
+		return nil
+	}
+	p := sel.pods[0]
+	return m.showDetail(detail{kind: ViewPods, ns: p.Namespace, name: p.PodName}, "")
+}
+
+// zoomOut goes back up a level, to the tile it came from.
+func (m *Model) zoomOut() {
+	t := &m.tree
+	if len(t.path) == 0 {
+		return
+	}
+	t.cur = t.path[len(t.path)-1]
+	t.path = t.path[:len(t.path)-1]
+}
+
+// updateTreemap handles keys while the treemap is shown.
+func (m Model) updateTreemap(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	k := m.keys
+	t := &m.tree
+	tm, tiles, _ := m.treeChart()
+	move := func(dx, dy int) {
+		if len(tiles) > 0 {
+			t.cur = tiles[tm.Next(tm.Layout(), dx, dy)].key
+		}
+	}
+	switch {
+	case key.Matches(msg, k.Back):
+		t.on = false
+	case key.Matches(msg, k.Quit):
+		t.on = false
+		return m.Update(msg)
+	case key.Matches(msg, k.ZoomIn):
+		return m, m.zoomIn()
+	case key.Matches(msg, k.ZoomOut):
+		m.zoomOut()
+	case key.Matches(msg, k.TreeMetric):
+		t.mem = !t.mem
+	case key.Matches(msg, k.TreeMeasure):
+		t.req = !t.req
+	case key.Matches(msg, k.Up):
+		move(0, -1)
+	case key.Matches(msg, k.Down):
+		move(0, 1)
+	case key.Matches(msg, k.Left):
+		move(-1, 0)
+	case key.Matches(msg, k.Right):
+		move(1, 0)
+	}
+	return m, nil
+}
+
+// treemapView is the page: a title with where the map is zoomed, the
+// selected tile and the map.
+func (m Model) treemapView() string {
+	t := m.tree
+	tm, tiles, empty := m.treeChart()
+	metric := map[bool]string{false: "CPU", true: "MEM"}[t.mem]
+	measure := map[bool]string{false: "usage", true: "requests"}[t.req]
+	crumbs := append([]string{"cluster"}, t.path...)
+	title := styles.Title.Render(fmt.Sprintf("Treemap: %s %s by %s", metric, measure, treeLevels[len(t.path)])) +
+		styles.Faint.Render("   "+strings.Join(crumbs, " › "))
+	if empty > 0 {
+		title += styles.Faint.Render(fmt.Sprintf("   %d without %s %s not shown", empty, metric, measure))
+	}
+
+	var cur string
+	switch {
+	case t.err != nil:
+		cur = styles.Danger.Render(t.err.Error())
+	case !t.loaded:
+		cur = styles.Faint.Render("loading…")
+	case len(tiles) == 0:
+		cur = styles.Faint.Render("nothing to show")
+	default:
+		sel := tiles[tm.Sel]
+		total := 0.0
+		for _, tt := range tiles {
+			total += tt.value
+		}
+		cur = fmt.Sprintf("%s   %s %s %s, %s of %s", styles.TabActive.Render(sel.key), metric, measure,
+			t.amount(sel.value), share(ratio(sel.value, total)), crumbs[len(crumbs)-1])
+		if len(t.path) < 2 {
+			cur += "   " + plural(len(sel.pods), "pod")
+		} else {
+			p := sel.pods[0]
+			cur += styles.Faint.Render(fmt.Sprintf("   %s  ready %s  on %s", p.Phase, p.Ready, dash(p.NodeName)))
+		}
+	}
+
+	body := title + "\n" + cur
+	if len(tiles) > 0 {
+		body += "\n" + tm.View()
+	}
+	lines := strings.Split(body, "\n")
+	for i, l := range lines {
+		lines[i] = ansi.Truncate(l, m.width-2, "…")
+	}
+	return lipgloss.NewStyle().Padding(0, 1).Height(m.height - 2).MaxHeight(m.height - 2).Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,244 @@
+// Package config loads and saves kmet's YAML config file.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/HaPhanBaoMinh/kmet/help"
+)
+
+type Config struct {
+	// Top-level settings apply to every kube context.
+	Settings `yaml:",inline"`
+
+	// Contexts override settings per kube context, by context name.
+	Contexts map[string]Settings `yaml:"contexts,omitempty"`
+
+	// Filters are named filter expressions, applied with ":filter @name".
+	Filters map[string]string `yaml:"filters,omitempty"`
+
+	// Themes are user palettes, usable as theme: <name>.
+	Themes map[string]Palette `yaml:"themes,omitempty"`
+}
+
+// Palette is a user theme: a built-in theme (dark unless Base says
+// otherwise) with some of its colors replaced. Colors are "#RRGGBB" or an
+// ANSI number.
+type Palette struct {
+	Base string `yaml:"base,omitempty"`
+
+	Accent   string `yaml:"accent,omitempty"` // active tab, popup borders, help keys
+	Muted    string `yaml:"muted,omitempty"`
+	Header   string `yaml:"header,omitempty"`
+	Footer   string `yaml:"footer,omitempty"`
+	Border   string `yaml:"border,omitempty"`
+	Selected string `yaml:"selected,omitempty"` // cursor row
+	Danger   string `yaml:"danger,omitempty"`
+	Warn     string `yaml:"warn,omitempty"`
+	Good     string `yaml:"good,omitempty"`
+	Faint    string `yaml:"faint,omitempty"`
+	Bar      string `yaml:"bar,omitempty"`
+	Spark    string `yaml:"spark,omitempty"`
+	MatchFG  string `yaml:"match-fg,omitempty"`
+	MatchBG  string `yaml:"match-bg,omitempty"`
+
+	// Sources replaces the colors log sources are hashed into.
+	Sources []string `yaml:"sources,omitempty"`
+}
+
+// Columns lists the columns of each view, left to right. Empty means the
+// built-in default.
+type Columns struct {
+	Pods  []Column `yaml:"pods,omitempty"`
+	Nodes []Column `yaml:"nodes,omitempty"`
+}
+
+// Column is a column name, optionally with a fixed width. In the file it is
+// either a plain name ("namespace", "label:app") or {name: node, width: 20}.
+type Column struct {
+	Name  string `yaml:"name"`
+	Width int    `yaml:"width,omitempty"` // 0 = sized to fit the terminal
+}
+
+func (c *Column) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		c.Name = n.Value
+		return nil
+	}
+	if n.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: a column is a name or {name, width}", n.Line)
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if k := n.Content[i]; k.Value != "name" && k.Value != "width" {
+			return fmt.Errorf("line %d: unknown column option %q (have: name, width)", k.Line, k.Value)
+		}
+	}
+	type plain Column
+	if err := n.Decode((*plain)(c)); err != nil {
+		return err
+	}
+	if c.Name == "" {
+		return fmt.Errorf("line %d: column without a name", n.Line)
+	}
+	if c.Width < 0 {
+		return fmt.Errorf("line %d: column %s: width must be positive", n.Line, c.Name)
+	}
+	return nil
+}
+
+// node is how a column is written back: a bare name unless it has a width.
+func (c Column) node() *yaml.Node {
+	if c.Width == 0 {
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: c.Name}
+	}
+	return &yaml.Node{Kind: yaml.MappingNode, Style: yaml.FlowStyle, Content: []*yaml.Node{
+		{Kind: yaml.ScalarNode, Value: "name"}, {Kind: yaml.ScalarNode, Value: c.Name},
+		{Kind: yaml.ScalarNode, Value: "width"}, {Kind: yaml.ScalarNode, Value: strconv.Itoa(c.Width)},
+	}}
+}
+
+// Path is $XDG_CONFIG_HOME/kmet/config.yaml, defaulting to ~/.config.
+func Path() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		dir = filepath.Join(help.HomeDir(), ".config")
+	}
+	return filepath.Join(dir, "kmet", "config.yaml")
+}
+
+// Load reads path. A missing file is an empty config, not an error; unknown
+// keys and out-of-range values are, so typos don't go unnoticed.
+func Load(path string) (Config, error) {
+	var c Config
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&c); err != nil && !errors.Is(err, io.EOF) {
+		return c, fmt.Errorf("%s: %s", path, yamlError(err))
+	}
+	if err := c.check(); err != nil {
+		return c, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+var notFoundRe = regexp.MustCompile(`field (\S+) not found in type \S+`)
+
+// yamlError flattens yaml.v3's multi-line errors and drops Go type names:
+// "line 3: unknown key "nmespace"".
+func yamlError(err error) string {
+	var te *yaml.TypeError
+	if !errors.As(err, &te) {
+		return strings.TrimPrefix(err.Error(), "yaml: ")
+	}
+	msgs := make([]string, len(te.Errors))
+	for i, e := range te.Errors {
+		msgs[i] = notFoundRe.ReplaceAllString(e, `unknown key "$1"`)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// SaveFilter stores a named filter in the file at path, creating it if
+// needed. The file is edited in place so comments and the rest of it are
+// kept.
+func SaveFilter(path, name, expr string) error {
+	return edit(path, func(root *yaml.Node) {
+		filters := mapping(root, "filters")
+		setScalar(filters, name, expr)
+	})
+}
+
+// SaveColumns stores the column layout of one view ("pods" or "nodes"), at
+// the top level or, when kubeContext is set, under contexts.<kubeContext>.
+func SaveColumns(path, kubeContext, view string, cols []Column) error {
+	return edit(path, func(root *yaml.Node) {
+		seq := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, c := range cols {
+			seq.Content = append(seq.Content, c.node())
+		}
+		if kubeContext != "" {
+			root = mapping(mapping(root, "contexts"), kubeContext)
+		}
+		set(mapping(root, "columns"), view, seq)
+	})
+}
+
+// edit applies fn to the document at path and writes it back.
+func edit(path string, fn func(root *yaml.Node)) error {
+	var doc yaml.Node
+	b, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if len(bytes.TrimSpace(b)) > 0 {
+		if err := yaml.Unmarshal(b, &doc); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: top level is not a mapping", path)
+	}
+	fn(root)
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, out.Bytes(), 0o644)
+}
+
+// mapping returns the mapping under key in m, adding an empty one if absent.
+func mapping(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			v := m.Content[i+1]
+			if v.Kind != yaml.MappingNode { // "filters:" with nothing under it
+				*v = yaml.Node{Kind: yaml.MappingNode}
+			}
+			return v
+		}
+	}
+	v := &yaml.Node{Kind: yaml.MappingNode}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, v)
+	return v
+}
+
+func setScalar(m *yaml.Node, key, value string) {
+	set(m, key, &yaml.Node{Kind: yaml.ScalarNode, Value: value})
+}
+
+// set replaces the value under key in m, or appends key: v.
+func set(m *yaml.Node, key string, v *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content[i+1] = v
+			return
+		}
+	}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, v)
+}
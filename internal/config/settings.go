@@ -0,0 +1,218 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Settings are the tunables that can be set at the top level and overridden
+// per kube context. Zero values mean "not set here".
+type Settings struct {
+	Namespace string        `yaml:"namespace,omitempty"` // start namespace; "all" for every namespace
+	View      string        `yaml:"view,omitempty"`      // start view: pods or nodes
+	Sort      Sort          `yaml:"sort,omitempty"`
+	Refresh   time.Duration `yaml:"refresh,omitempty"` // metrics refresh interval, e.g. 2s
+
+	// Columns picks, orders and sizes each table's columns.
+	Columns Columns `yaml:"columns,omitempty"`
+
+	// Thresholds color usage, by metric ("cpu/req", "mem/lim", "node.cpu"...).
+	Thresholds map[string]Level `yaml:"thresholds,omitempty"`
+
+	Theme string `yaml:"theme,omitempty"`
+
+	// Keys rebinds actions: find: ["/", "ctrl+f"].
+	Keys map[string]Keys `yaml:"keys,omitempty"`
+
+	// LogFields are the fields of JSON and logfmt lines the logs pane shows,
+	// in order; empty shows them all.
+	LogFields []string `yaml:"log-fields,omitempty"`
+
+	// Dashboard lays out the panes of the dashboard.
+	Dashboard Dashboard `yaml:"dashboard,omitempty"`
+}
+
+// Sort is the start sort of each view: a key and an optional direction,
+// "mem desc".
+type Sort struct {
+	Pods  string `yaml:"pods,omitempty"`
+	Nodes string `yaml:"nodes,omitempty"`
+}
+
+// Level is where a metric turns yellow (Warn) and red (Crit), in percent.
+type Level struct {
+	Warn float64 `yaml:"warn"`
+	Crit float64 `yaml:"crit"`
+}
+
+// Keys is a list of keys in bubbletea notation ("ctrl+f", "G"); a single
+// key may be written without the list.
+type Keys []string
+
+func (k *Keys) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		*k = Keys{n.Value}
+		return nil
+	}
+	var l []string
+	if err := n.Decode(&l); err != nil {
+		return fmt.Errorf("line %d: keys are a key or a list of keys", n.Line)
+	}
+	*k = l
+	return nil
+}
+
+// Dashboard is rows of panes, top to bottom. Rows share the height and a
+// row's panes its width, each in proportion to its weight.
+type Dashboard struct {
+	Rows []DashRow `yaml:"rows,omitempty"`
+}
+
+type DashRow struct {
+	Weight int        `yaml:"weight,omitempty"` // 1 when not set
+	Panes  []DashPane `yaml:"panes"`
+}
+
+// DashPane is a pane of a row: a name, or {pane: ..., weight: ...}.
+type DashPane struct {
+	Pane   string `yaml:"pane"`
+	Weight int    `yaml:"weight,omitempty"` // 1 when not set
+}
+
+func (p *DashPane) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		p.Pane = n.Value
+		return nil
+	}
+	if n.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: a pane is a name or {pane, weight}", n.Line)
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if k := n.Content[i]; k.Value != "pane" && k.Value != "weight" {
+			return fmt.Errorf("line %d: unknown pane option %q (have: pane, weight)", k.Line, k.Value)
+		}
+	}
+	type plain DashPane
+	if err := n.Decode((*plain)(p)); err != nil {
+		return err
+	}
+	if p.Pane == "" {
+		return fmt.Errorf("line %d: pane without a name", n.Line)
+	}
+	return nil
+}
+
+// For returns the settings for a kube context: the top level with that
+// context's overrides on top.
+func (c Config) For(kubeContext string) Settings {
+	s := c.Settings
+	o, ok := c.Contexts[kubeContext]
+	if !ok {
+		return s
+	}
+	s.Namespace = Or(o.Namespace, s.Namespace)
+	s.View = Or(o.View, s.View)
+	s.Sort.Pods = Or(o.Sort.Pods, s.Sort.Pods)
+	s.Sort.Nodes = Or(o.Sort.Nodes, s.Sort.Nodes)
+	s.Refresh = Or(o.Refresh, s.Refresh)
+	s.Theme = Or(o.Theme, s.Theme)
+	if len(o.Columns.Pods) > 0 {
+		s.Columns.Pods = o.Columns.Pods
+	}
+	if len(o.Columns.Nodes) > 0 {
+		s.Columns.Nodes = o.Columns.Nodes
+	}
+	if len(o.LogFields) > 0 {
+		s.LogFields = o.LogFields
+	}
+	if len(o.Dashboard.Rows) > 0 {
+		s.Dashboard = o.Dashboard
+	}
+	s.Thresholds = merge(s.Thresholds, o.Thresholds)
+	s.Keys = merge(s.Keys, o.Keys)
+	return s
+}
+
+// Or returns v, or def when v is the zero value. It is a plain generic
+// fallback with no config semantics; the UI uses it for its own defaults.
+func Or[T comparable](v, def T) T {
+	var zero T
+	if v == zero {
+		return def
+	}
+	return v
+}
+
+func merge[V any](base, over map[string]V) map[string]V {
+	if len(over) == 0 {
+		return base
+	}
+	out := make(map[string]V, len(base)+len(over))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range over {
+		out[k] = v
+	}
+	return out
+}
+
+// Each calls fn with the top-level settings (prefix "") and then every
+// context's overrides (prefix "contexts.<name>."), in name order; it stops
+// at the first error.
+func (c Config) Each(fn func(prefix string, s Settings) error) error {
+	if err := fn("", c.Settings); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(c.Contexts))
+	for n := range c.Contexts {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if err := fn("contexts."+n+".", c.Contexts[n]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check validates what the config package knows about; names of columns,
+// sort keys, actions and themes are up to the app.
+func (c Config) check() error {
+	return c.Each(func(prefix string, s Settings) error {
+		if s.View != "" && s.View != "pods" && s.View != "nodes" {
+			return fmt.Errorf("%sview: want pods or nodes, got %q", prefix, s.View)
+		}
+		if s.Refresh != 0 && s.Refresh < 250*time.Millisecond {
+			return fmt.Errorf("%srefresh: %s is too fast, use 250ms or more", prefix, s.Refresh)
+		}
+		for name, l := range s.Thresholds {
+			if l.Warn <= 0 || l.Crit <= 0 || l.Warn > l.Crit {
+				return fmt.Errorf("%sthresholds.%s: want 0 < warn <= crit, got warn %g crit %g", prefix, name, l.Warn, l.Crit)
+			}
+		}
+		for action, keys := range s.Keys {
+			if len(keys) == 0 {
+				return fmt.Errorf("%skeys.%s: no keys given", prefix, action)
+			}
+		}
+		for i, r := range s.Dashboard.Rows {
+			if r.Weight < 0 {
+				return fmt.Errorf("%sdashboard.rows[%d].weight: must be positive, got %d", prefix, i, r.Weight)
+			}
+			if len(r.Panes) == 0 {
+				return fmt.Errorf("%sdashboard.rows[%d]: a row needs panes", prefix, i)
+			}
+			for j, p := range r.Panes {
+				if p.Weight < 0 {
+					return fmt.Errorf("%sdashboard.rows[%d].panes[%d].weight: must be positive, got %d", prefix, i, j, p.Weight)
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Template is what "kmet config init" writes: the defaults, spelled out,
+// with everything optional commented.
+const Template = `# kmet configuration. Everything here is optional; delete what you don't
+# change. Settings at the top level apply to every kube context and can be
+# overridden under contexts: below.
+
+# Namespace to start in ("all" for every namespace) and the first view.
+namespace: default
+view: pods             # pods or nodes
+
+# Start sort of each view: a key and optionally asc/desc.
+# Pods: cpu mem cpu/req mem/req err restarts ready age name namespace node
+# Nodes: cpu mem pods name version
+sort:
+  pods: cpu desc
+  nodes: cpu desc
+
+# How often metrics are fetched.
+refresh: 2s
+
+# Color theme: dark, light, solarized, high-contrast, monochrome or one of
+# the palettes below. Without color support (NO_COLOR set, a dumb terminal)
+# kmet is monochrome whatever is set here.
+theme: dark
+
+# Your own palettes: a built-in theme with some colors replaced. Colors are
+# "#RRGGBB" or an ANSI number 0-255. Replaceable: accent muted header footer
+# border selected danger warn good faint bar spark match-fg match-bg sources.
+# themes:
+#   mine:
+#     base: light
+#     accent: "#005FAF"
+#     bar: "33"
+
+# Columns of each table, left to right. A column is a name, or
+# {name: ..., width: ...} to pin its width. Press "c" in kmet to pick them
+# interactively; "w" in the chooser saves here.
+# columns:
+#   pods: [pod, namespace, cpu, cpu.bar, mem, mem.bar, qos, restarts, label:app, node]
+#   nodes: [name, cpu, cpu.bar, mem, mem.bar, pods, version, trend]
+
+# Where usage turns yellow (warn) and red (crit), in percent of the
+# request, the limit, the node's allocatable (cpu/node: a pod's share) or,
+# for node.cpu/node.mem, a node's own usage.
+# thresholds:
+#   cpu/req: {warn: 80, crit: 100}
+#   mem/req: {warn: 80, crit: 100}
+#   cpu/lim: {warn: 80, crit: 95}
+#   mem/lim: {warn: 80, crit: 95}
+#   cpu/node: {warn: 25, crit: 50}
+#   mem/node: {warn: 25, crit: 50}
+#   node.cpu: {warn: 70, crit: 90}
+#   node.mem: {warn: 70, crit: 90}
+
+# Rebind actions: a key or a list of keys. "?" in kmet lists the actions
+# of the current mode.
+# keys:
+#   find: ["/", "ctrl+p"]
+#   quit: q
+
+# Fields of JSON and logfmt log lines shown in the logs pane, in order;
+# level, message and time are always pulled out. Unset shows every field.
+# log-fields: [status, dur, err]
+
+# The dashboard ("B") as rows of panes, top to bottom. Rows share the
+# height and a row's panes its width by weight (1 when not set). A pane is
+# a name or {pane: ..., weight: ...}; panes: pods (the top pods by CPU),
+# nodes (node utilization), events (recent warnings) and logs (of the pod
+# pinned with "p").
+# dashboard:
+#   rows:
+#     - weight: 3
+#       panes: [{pane: pods, weight: 2}, nodes]
+#     - weight: 2
+#       panes: [events, {pane: logs, weight: 2}]
+
+# Per kube context overrides, by context name.
+# contexts:
+#   prod-eu:
+#     namespace: payments
+#     refresh: 5s
+#     theme: light
+
+# Named filters for ":filter @name"; ":save <name>" adds to them.
+# filters:
+#   hot: cpu/req > 0.9 || mem/req > 0.9
+`
+
+// Init writes Template to path. An existing file is left alone unless
+// force is set.
+func Init(path string, force bool) error {
+	if _, err := os.Stat(path); err == nil && !force {
+		return fmt.Errorf("%s already exists (use -force to overwrite)", path)
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(Template), 0o644)
+}
@@ -5,21 +5,37 @@ import "time"
 type Trend struct {
 	Samples []float64 // normalized 0..1
 	Window  time.Duration
+
+	// Values are the samples in the metric's unit (millicores, bytes, or
+	// the 0..1 share of allocatable for nodes); Times is when each was taken.
+	Values []float64
+	Times  []time.Time
 }
 
 type PodMetric struct {
-	Namespace   string
-	PodName     string
-	Container   string
-	NodeName    string
-	CPUm        int    // millicores used
-	MemBytes    int64  // bytes used
-	CPUReqm     int    // request cpu
-	MemReqBytes int64  // request mem
-	Ready       string // "1/1", "2/3", ...
-	Phase       string // Running, Pending...
-	CPUTrend    Trend
-	MemTrend    Trend
+	Namespace    string
+	PodName      string
+	Container    string
+	NodeName     string
+	CPUm         int    // millicores used
+	MemBytes     int64  // bytes used
+	CPUReqm      int    // request cpu
+	MemReqBytes  int64  // request mem
+	CPULimm      int    // limit cpu, 0 = none
+	MemLimBytes  int64  // limit mem, 0 = none
+	QoS          string // Guaranteed, Burstable, BestEffort
+	Labels       map[string]string
+	NodeCPUm     int      // allocatable cpu of the pod's node, 0 = unknown
+	NodeMemBytes int64    // allocatable mem of the pod's node, 0 = unknown
+	NodePressure []string // pressure conditions of the pod's node, see NodeMetric
+	Ready        string   // "1/1", "2/3", ...
+	Phase        string   // Running, Pending...
+	Restarts     int      // container restarts, summed over the pod
+	Created      time.Time
+	OwnerKind    string // Deployment, StatefulSet, DaemonSet, Job... ("" for bare pods)
+	OwnerName    string
+	CPUTrend     Trend
+	MemTrend     Trend
 }
 
 type NodeMetric struct {
@@ -30,11 +46,102 @@ type NodeMetric struct {
 	K8sVer   string
 	CPUTrend Trend
 	MEMTrend Trend
+
+	CPUAllocm     int   // allocatable cpu, 0 = unknown
+	MemAllocBytes int64 // allocatable mem, 0 = unknown
+	CPUReqm       int   // requests of the pods on it, summed
+	MemReqBytes   int64
+
+	Pressure []string // conditions that are True: MemoryPressure, DiskPressure, PIDPressure
+	NotReady bool     // the Ready condition is not True
+	Labels   map[string]string
+}
+
+// PodDetail is what the detail view shows of a pod beyond its metrics.
+type PodDetail struct {
+	IP             string
+	ServiceAccount string
+	QoS            string
+	Started        time.Time
+	Containers     []ContainerDetail // init containers first
+	Conditions     []Condition
+	Events         []Event // oldest first
+}
+
+type ContainerDetail struct {
+	Name  string
+	Image string
+	Init  bool
+
+	CPUReqm, CPULimm         int // 0 = not set
+	MemReqBytes, MemLimBytes int64
+
+	Ready    bool
+	State    string // "Running", "Waiting: CrashLoopBackOff", "Terminated: Completed"...
+	Restarts int
+	LastExit string // why the previous instance ended ("OOMKilled (137)"), "" = none
+
+	Liveness, Readiness, Startup string // "http-get :8080/healthz every 10s", "" = none
+}
+
+// NodeDetail is what the detail view shows of a node beyond its metrics.
+type NodeDetail struct {
+	Capacity, Allocatable Resources
+
+	Conditions    []Condition
+	Taints        []string // "key=value:NoSchedule"
+	Labels        map[string]string
+	Addresses     []string // "InternalIP 10.0.1.5"
+	Unschedulable bool
+
+	OS, Kernel, Runtime, Kubelet string
+	Created                      time.Time
+	Events                       []Event // oldest first
+}
+
+type Resources struct {
+	CPUm      int
+	MemBytes  int64
+	Pods      int
+	DiskBytes int64 // ephemeral-storage
+}
+
+type Condition struct {
+	Type    string
+	Status  string // True, False, Unknown
+	Reason  string
+	Message string
+	Since   time.Time // last transition
+}
+
+type Event struct {
+	Type    string // Normal, Warning
+	Reason  string
+	Message string
+	Count   int
+	Last    time.Time
+
+	// What the event is about, set by listings across objects.
+	Namespace string
+	Object    string // "Pod/api-7cfb9d9c9c-9tghd", "Node/ip-10-0-1-5"
 }
 
 type LogLine struct {
-	Time   time.Time
-	Level  string // INFO/WARN/ERROR
-	Text   string
-	Source string // pod/container or owner
+	Time     time.Time
+	Received time.Time // the kubelet's timestamp; Time may be replaced by one the line carries
+	Level    string    // DEBUG/INFO/WARN/ERROR/FATAL
+	Text     string    // raw line as received
+	Source   string    // pod/container or owner
+
+	Dropped int // lines the producer dropped right before this one (slow reader)
+
+	// Filled in by a structured parser (JSON, logfmt...); empty for plain lines.
+	Format string
+	Msg    string
+	Fields []Field
+}
+
+type Field struct {
+	Key   string
+	Value string
 }
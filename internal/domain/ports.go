@@ -1,20 +1,63 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type MetricsRepo interface {
-	ListPods(ctx context.Context, ns string, selector string) ([]PodMetric, error)
+	ListPods(ctx context.Context, ns string, sel Selector) ([]PodMetric, error)
 	ListNodes(ctx context.Context) ([]NodeMetric, error)
 	ListNamespaces(ctx context.Context) ([]string, error)
 }
 
+// Selector narrows a pod listing, in kubectl syntax: Labels like
+// "app=api,tier!=cache", Fields like "status.phase!=Running,spec.nodeName=x".
+type Selector struct {
+	Labels string
+	Fields string
+}
+
 type LogsTarget struct {
 	Namespace string
 	Kind      string // "Pod","Deployment","Node"...
 	Name      string
 	Container string
+	Service   string // Node only: journal unit ("kubelet") or file under /var/log; "" = node events
+
+	// Range of history to backfill before following. SinceTime wins over
+	// SinceSeconds when both are set; zero values mean "server default".
+	TailLines    int64
+	SinceSeconds int64
+	SinceTime    time.Time
+	Previous     bool // logs of the previous (crashed) container instance
 }
 
 type LogsRepo interface {
 	StreamLogs(ctx context.Context, t LogsTarget) (<-chan LogLine, error)
 }
+
+// NodeLogSources is implemented by LogsRepos that can query node logs; it
+// lists what can be asked for on a node (journal units, /var/log files).
+type NodeLogSources interface {
+	NodeLogServices(ctx context.Context, node string) ([]string, error)
+}
+
+// Describer is implemented by MetricsRepos that can fetch what the detail
+// view shows of a pod or node: containers, conditions, events...
+type Describer interface {
+	DescribePod(ctx context.Context, ns, name string) (PodDetail, error)
+	DescribeNode(ctx context.Context, name string) (NodeDetail, error)
+}
+
+// Manifests is implemented by MetricsRepos that can fetch an object as
+// `kubectl get -o yaml` prints it; kind is "Pod" or "Node".
+type Manifests interface {
+	Manifest(ctx context.Context, kind, ns, name string) ([]byte, error)
+}
+
+// Warnings is implemented by MetricsRepos that can list the recent warning
+// events of a namespace ("all" for every one), newest first.
+type Warnings interface {
+	ListWarnings(ctx context.Context, ns string) ([]Event, error)
+}
@@ -0,0 +1,256 @@
+// Package filter is a small expression language for narrowing the pod and
+// node tables:
+//
+//	cpu > 200m && mem/req > 0.9 && restarts > 0
+//	node =~ "ip-10-0-2-.*" || phase != "Running"
+//
+// Numbers accept Kubernetes quantity suffixes (200m, 512Mi, 2Gi, 1k) and a
+// trailing % (80% == 0.8). Strings compare with == != < > and match regular
+// expressions with =~ and !~. && / || / ! also read as and / or / not.
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kind is the type of a field or expression.
+type Kind int
+
+const (
+	Number Kind = iota
+	String
+	Bool
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Number:
+		return "number"
+	case String:
+		return "string"
+	default:
+		return "bool"
+	}
+}
+
+// Schema lists the fields an expression may use and their kinds. Names may
+// contain '/' (mem/req); "mem / req" with spaces is still a division.
+type Schema map[string]Kind
+
+func (s Schema) names() string {
+	names := make([]string, 0, len(s))
+	for n := range s {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// Env supplies field values during evaluation. Numbers are float64, strings
+// string; a field missing from the row evaluates as zero / "".
+type Env interface {
+	Field(name string) any
+}
+
+// Expr is a compiled filter.
+type Expr struct {
+	src  string
+	root node
+}
+
+func (e *Expr) String() string { return e.src }
+
+// Match reports whether the row described by env passes the filter.
+func (e *Expr) Match(env Env) bool {
+	return truthy(e.root.eval(env))
+}
+
+// Error is a compile error; Pos is the byte offset in the source it refers to.
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string { return fmt.Sprintf("col %d: %s", e.Pos+1, e.Msg) }
+
+// Compile parses src and type-checks it against schema.
+func Compile(src string, schema Schema) (*Expr, error) {
+	toks, err := lex(src, schema)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks, schema: schema}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tEOF {
+		return nil, &Error{t.pos, fmt.Sprintf("unexpected %s", t)}
+	}
+	return &Expr{src: src, root: root}, nil
+}
+
+type node interface {
+	eval(Env) any
+	kind() Kind
+}
+
+type (
+	numLit  float64
+	strLit  string
+	boolLit bool
+	field   struct {
+		name string
+		k    Kind
+	}
+	unary struct {
+		op string
+		x  node
+	}
+	binary struct {
+		op   string
+		l, r node
+	}
+	match struct {
+		negate bool
+		x      node
+		re     *regexp.Regexp
+	}
+)
+
+func (n numLit) eval(Env) any  { return float64(n) }
+func (numLit) kind() Kind      { return Number }
+func (n strLit) eval(Env) any  { return string(n) }
+func (strLit) kind() Kind      { return String }
+func (n boolLit) eval(Env) any { return bool(n) }
+func (boolLit) kind() Kind     { return Bool }
+
+func (f field) eval(env Env) any {
+	v := env.Field(f.name)
+	switch f.k {
+	case Number:
+		if n, ok := v.(float64); ok {
+			return n
+		}
+		return 0.0
+	case String:
+		if s, ok := v.(string); ok {
+			return s
+		}
+		return ""
+	default:
+		return truthy(v)
+	}
+}
+func (f field) kind() Kind { return f.k }
+
+func (u unary) eval(env Env) any {
+	v := u.x.eval(env)
+	if u.op == "-" {
+		return -v.(float64)
+	}
+	return !truthy(v)
+}
+func (u unary) kind() Kind {
+	if u.op == "-" {
+		return Number
+	}
+	return Bool
+}
+
+func (b binary) eval(env Env) any {
+	switch b.op {
+	case "&&":
+		return truthy(b.l.eval(env)) && truthy(b.r.eval(env))
+	case "||":
+		return truthy(b.l.eval(env)) || truthy(b.r.eval(env))
+	}
+	l, r := b.l.eval(env), b.r.eval(env)
+	switch b.op {
+	case "+":
+		return l.(float64) + r.(float64)
+	case "-":
+		return l.(float64) - r.(float64)
+	case "*":
+		return l.(float64) * r.(float64)
+	case "/":
+		if r.(float64) == 0 {
+			return 0.0 // no request / no capacity: treat the ratio as 0
+		}
+		return l.(float64) / r.(float64)
+	}
+	c := compare(l, r)
+	switch b.op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	default: // ">="
+		return c >= 0
+	}
+}
+
+func (b binary) kind() Kind {
+	switch b.op {
+	case "+", "-", "*", "/":
+		return Number
+	}
+	return Bool
+}
+
+func (m match) eval(env Env) any {
+	return m.re.MatchString(m.x.eval(env).(string)) != m.negate
+}
+func (match) kind() Kind { return Bool }
+
+func compare(l, r any) int {
+	switch lv := l.(type) {
+	case float64:
+		rv := r.(float64)
+		switch {
+		case lv < rv:
+			return -1
+		case lv > rv:
+			return 1
+		}
+		return 0
+	case string:
+		rv := r.(string)
+		switch {
+		case lv < rv:
+			return -1
+		case lv > rv:
+			return 1
+		}
+		return 0
+	default:
+		if truthy(l) == truthy(r) {
+			return 0
+		}
+		if !truthy(l) {
+			return -1
+		}
+		return 1
+	}
+}
+
+func truthy(v any) bool {
+	switch t := v.(type) {
+	case bool:
+		return t
+	case float64:
+		return t != 0
+	case string:
+		return t != ""
+	}
+	return false
+}
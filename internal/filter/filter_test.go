@@ -0,0 +1,128 @@
+package filter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var testSchema = Schema{
+	"cpu":      Number,
+	"mem":      Number,
+	"req":      Number,
+	"mem/req":  Number,
+	"restarts": Number,
+	"node":     String,
+	"phase":    String,
+	"ns":       String,
+	"ready":    Bool,
+}
+
+type row map[string]any
+
+func (r row) Field(name string) any { return r[name] }
+
+var testRow = row{
+	"cpu":      0.25,
+	"mem":      float64(1 << 30),
+	"req":      float64(512 << 20),
+	"mem/req":  0.95,
+	"restarts": 2.0,
+	"node":     "ip-10-0-2-7",
+	"phase":    "Running",
+	"ready":    true,
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{"cpu > 200m", true},
+		{"cpu > 300m", false},
+		{"mem == 1Gi", true},
+		{"1e3 == 1k", true},
+		{"2E > 1e18", true},
+
+		// mem/req is a field; spaced or unknown right-hand names divide
+		{"mem/req > 0.9", true},
+		{"mem / req > 1.9", true},
+		{"mem/req < 1", true},
+		{"cpu / 0 == 0", true},
+
+		{"mem/req > 90%", true},
+		{"mem/req > 96%", false},
+		{"80% == 0.8", true},
+		{"cpu == 25%", true},
+
+		{`node =~ "ip-10-0-2-.*"`, true},
+		{`node !~ 'ip-10'`, false},
+		{`node =~ "ip-\d+-0"`, true},
+		{`phase == "Run\"ning"`, false},
+
+		{"phase = 'Running' and not ready", false},
+		{`restarts > 0 or phase != "Running"`, true},
+		{"!(cpu > 1) && ready", true},
+		{"ready", true},
+		{"ready == true", true},
+		{"1 + 2 * 3 == 7", true},
+		{"(1 + 2) * 3 == 9", true},
+		{"-cpu < 0", true},
+		{"- -cpu > 0", true},
+		{`node > "ip"`, true},
+
+		// fields missing from the row read as zero / ""
+		{`ns == ""`, true},
+	}
+	for _, tt := range tests {
+		e, err := Compile(tt.src, testSchema)
+		if err != nil {
+			t.Errorf("Compile(%q): %v", tt.src, err)
+			continue
+		}
+		if got := e.Match(testRow); got != tt.want {
+			t.Errorf("%q: got %v, want %v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		pos int
+		msg string
+	}{
+		{"cpu >", 5, "expected a value, got end of expression"},
+		{"cpu > 2 )", 8, `unexpected ")"`},
+		{"(cpu > 1", 8, "expected ), got end of expression"},
+		{"cpu # 1", 4, "unexpected character"},
+		{"foo > 1", 0, `unknown field "foo"`},
+		{"mem/x > 1", 4, `unknown field "x"`},
+
+		{"cpu > 2q", 6, `bad quantity "2q"`},
+		{"cpu > 1x%", 6, `bad percentage "1x%"`},
+		{`node == "abc`, 8, "unterminated string"},
+
+		{"node =~ ip", 8, `=~ needs a quoted regular expression, got "ip"`},
+		{"node !~ 1", 8, "!~ needs a quoted regular expression"},
+		{`node =~ "("`, 8, "missing closing )"},
+		{`cpu =~ "x"`, 0, "=~ needs a string on the left, got a number"},
+
+		{`cpu > "a"`, 6, "can't compare number with string"},
+		{"ready == 1", 9, "can't compare bool with number"},
+		{"node + 1", 0, "+ needs numbers, got a string"},
+		{"1 * node", 4, "* needs numbers, got a string"},
+		{"-node == 1", 0, "- needs a number"},
+	}
+	for _, tt := range tests {
+		_, err := Compile(tt.src, testSchema)
+		var fe *Error
+		if !errors.As(err, &fe) {
+			t.Errorf("Compile(%q): got %v, want an *Error", tt.src, err)
+			continue
+		}
+		if fe.Pos != tt.pos || !strings.Contains(fe.Msg, tt.msg) {
+			t.Errorf("Compile(%q): got %d %q, want %d %q", tt.src, fe.Pos, fe.Msg, tt.pos, tt.msg)
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+type tokKind int
+
+const (
+	tEOF tokKind = iota
+	tNum
+	tStr
+	tIdent
+	tOp
+)
+
+type token struct {
+	kind tokKind
+	pos  int
+	text string
+	num  float64
+}
+
+func (t token) String() string {
+	if t.kind == tEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// ops is ordered so two-character operators win over their prefixes.
+var ops = []string{"&&", "||", "==", "!=", "<=", ">=", "=~", "!~", "<", ">", "!", "=", "+", "-", "*", "/", "(", ")"}
+
+var keywords = map[string]string{"and": "&&", "or": "||", "not": "!"}
+
+func lex(src string, schema Schema) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case isDigit(c) || (c == '.' && i+1 < len(src) && isDigit(src[i+1])):
+			t, n, err := lexNumber(src, i)
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, t)
+			i += n
+		case c == '"' || c == '\'':
+			t, n, err := lexString(src, i)
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, t)
+			i += n
+		case isIdentStart(c):
+			j := i
+			for j < len(src) && isIdent(src[j]) {
+				j++
+			}
+			word := src[i:j]
+			// compound fields like "mem/req" read as one name; "mem / 2"
+			// and "mem/x" stay division
+			if j < len(src) && src[j] == '/' {
+				k := j + 1
+				for k < len(src) && isIdent(src[k]) {
+					k++
+				}
+				if _, ok := schema[word+src[j:k]]; ok && k > j+1 {
+					word, j = word+src[j:k], k
+				}
+			}
+			if op, ok := keywords[strings.ToLower(word)]; ok {
+				toks = append(toks, token{kind: tOp, pos: i, text: op})
+			} else {
+				toks = append(toks, token{kind: tIdent, pos: i, text: word})
+			}
+			i = j
+		default:
+			matched := false
+			for _, op := range ops {
+				if strings.HasPrefix(src[i:], op) {
+					text := op
+					if op == "=" {
+						text = "=="
+					}
+					toks = append(toks, token{kind: tOp, pos: i, text: text})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, &Error{i, fmt.Sprintf("unexpected character %q", c)}
+			}
+		}
+	}
+	return append(toks, token{kind: tEOF, pos: len(src)}), nil
+}
+
+// lexNumber reads a number with an optional quantity suffix or %.
+func lexNumber(src string, i int) (token, int, error) {
+	j := i
+	for j < len(src) && (isDigit(src[j]) || src[j] == '.') {
+		j++
+	}
+	// exponent, but not the E (exa) suffix on its own
+	if j+1 < len(src) && (src[j] == 'e' || src[j] == 'E') && (isDigit(src[j+1]) || src[j+1] == '-' || src[j+1] == '+') {
+		j += 2
+		for j < len(src) && isDigit(src[j]) {
+			j++
+		}
+	}
+	k := j
+	for k < len(src) && isLetter(src[k]) {
+		k++
+	}
+	text := src[i:k]
+	if k < len(src) && src[k] == '%' {
+		q, err := resource.ParseQuantity(src[i:j])
+		if err != nil || k > j {
+			return token{}, 0, &Error{i, fmt.Sprintf("bad percentage %q", src[i:k+1])}
+		}
+		return token{kind: tNum, pos: i, text: src[i : k+1], num: q.AsApproximateFloat64() / 100}, k + 1 - i, nil
+	}
+	q, err := resource.ParseQuantity(text)
+	if err != nil {
+		return token{}, 0, &Error{i, fmt.Sprintf("bad quantity %q (want e.g. 200m, 1.5, 512Mi, 2Gi)", text)}
+	}
+	return token{kind: tNum, pos: i, text: text, num: q.AsApproximateFloat64()}, k - i, nil
+}
+
+// lexString reads a quoted string. Only the quote and backslash can be
+// escaped; other backslashes are kept so regular expressions read naturally.
+func lexString(src string, i int) (token, int, error) {
+	q := src[i]
+	var b strings.Builder
+	for j := i + 1; j < len(src); j++ {
+		switch {
+		case src[j] == '\\' && j+1 < len(src) && (src[j+1] == q || src[j+1] == '\\'):
+			b.WriteByte(src[j+1])
+			j++
+		case src[j] == q:
+			return token{kind: tStr, pos: i, text: b.String()}, j + 1 - i, nil
+		default:
+			b.WriteByte(src[j])
+		}
+	}
+	return token{}, 0, &Error{i, "unterminated string"}
+}
+
+func isDigit(c byte) bool      { return c >= '0' && c <= '9' }
+func isLetter(c byte) bool     { return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+func isIdentStart(c byte) bool { return isLetter(c) || c == '_' }
+func isIdent(c byte) bool      { return isIdentStart(c) || isDigit(c) || c == '.' }
+
+// parser is a recursive-descent parser; precedence from loosest:
+// || , && , ! , comparisons , + - , * / , unary -.
+type parser struct {
+	toks   []token
+	i      int
+	schema Schema
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) accept(ops ...string) (token, bool) {
+	t := p.peek()
+	if t.kind != tOp {
+		return t, false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return p.next(), true
+		}
+	}
+	return t, false
+}
+
+func (p *parser) parseOr() (node, error) {
+	return p.parseLogical("||", p.parseAnd)
+}
+
+func (p *parser) parseAnd() (node, error) {
+	return p.parseLogical("&&", p.parseNot)
+}
+
+func (p *parser) parseLogical(op string, sub func() (node, error)) (node, error) {
+	l, err := sub()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept(op); !ok {
+			return l, nil
+		}
+		r, err := sub()
+		if err != nil {
+			return nil, err
+		}
+		l = binary{op, l, r}
+	}
+}
+
+func (p *parser) parseNot() (node, error) {
+	if _, ok := p.accept("!"); ok {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return unary{"!", x}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (node, error) {
+	lpos := p.peek().pos
+	l, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	t, ok := p.accept("==", "!=", "<", "<=", ">", ">=", "=~", "!~")
+	if !ok {
+		return l, nil
+	}
+	if t.text == "=~" || t.text == "!~" {
+		if l.kind() != String {
+			return nil, &Error{lpos, fmt.Sprintf("%s needs a string on the left, got a %s", t.text, l.kind())}
+		}
+		rt := p.next()
+		if rt.kind != tStr {
+			return nil, &Error{rt.pos, fmt.Sprintf("%s needs a quoted regular expression, got %s", t.text, rt)}
+		}
+		re, err := regexp.Compile(rt.text)
+		if err != nil {
+			return nil, &Error{rt.pos, err.Error()}
+		}
+		return match{negate: t.text == "!~", x: l, re: re}, nil
+	}
+	rpos := p.peek().pos
+	r, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if l.kind() != r.kind() {
+		return nil, &Error{rpos, fmt.Sprintf("can't compare %s with %s", l.kind(), r.kind())}
+	}
+	return binary{t.text, l, r}, nil
+}
+
+func (p *parser) parseSum() (node, error) {
+	return p.parseArith([]string{"+", "-"}, p.parseProduct)
+}
+
+func (p *parser) parseProduct() (node, error) {
+	return p.parseArith([]string{"*", "/"}, p.parseUnary)
+}
+
+func (p *parser) parseArith(ops []string, sub func() (node, error)) (node, error) {
+	lpos := p.peek().pos
+	l, err := sub()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.accept(ops...)
+		if !ok {
+			return l, nil
+		}
+		rpos := p.peek().pos
+		r, err := sub()
+		if err != nil {
+			return nil, err
+		}
+		if l.kind() != Number {
+			return nil, &Error{lpos, fmt.Sprintf("%s needs numbers, got a %s", t.text, l.kind())}
+		}
+		if r.kind() != Number {
+			return nil, &Error{rpos, fmt.Sprintf("%s needs numbers, got a %s", t.text, r.kind())}
+		}
+		l = binary{t.text, l, r}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if t, ok := p.accept("-"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if x.kind() != Number {
+			return nil, &Error{t.pos, "- needs a number"}
+		}
+		return unary{"-", x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tNum:
+		return numLit(t.num), nil
+	case tStr:
+		return strLit(t.text), nil
+	case tIdent:
+		switch strings.ToLower(t.text) {
+		case "true":
+			return boolLit(true), nil
+		case "false":
+			return boolLit(false), nil
+		}
+		k, ok := p.schema[t.text]
+		if !ok {
+			return nil, &Error{t.pos, fmt.Sprintf("unknown field %q (have %s)", t.text, p.schema.names())}
+		}
+		return field{t.text, k}, nil
+	case tOp:
+		if t.text == "(" {
+			x, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if c := p.next(); c.kind != tOp || c.text != ")" {
+				return nil, &Error{c.pos, fmt.Sprintf("expected ), got %s", c)}
+			}
+			return x, nil
+		}
+	}
+	return nil, &Error{t.pos, fmt.Sprintf("expected a value, got %s", t)}
+}
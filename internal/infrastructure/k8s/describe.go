@@ -0,0 +1,220 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+)
+
+// -------- Describer --------
+
+func (r *Repo) DescribePod(ctx context.Context, ns, name string) (domain.PodDetail, error) {
+	p, err := r.core.CoreV1().Pods(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return domain.PodDetail{}, err
+	}
+	d := domain.PodDetail{
+		IP:             p.Status.PodIP,
+		ServiceAccount: p.Spec.ServiceAccountName,
+		QoS:            string(p.Status.QOSClass),
+	}
+	if p.Status.StartTime != nil {
+		d.Started = p.Status.StartTime.Time
+	}
+	d.Containers = append(containers(p.Spec.InitContainers, p.Status.InitContainerStatuses, true),
+		containers(p.Spec.Containers, p.Status.ContainerStatuses, false)...)
+	for _, c := range p.Status.Conditions {
+		d.Conditions = append(d.Conditions, domain.Condition{
+			Type: string(c.Type), Status: string(c.Status), Reason: c.Reason, Message: c.Message, Since: c.LastTransitionTime.Time,
+		})
+	}
+	d.Events = r.events(ctx, ns, "Pod", name)
+	return d, nil
+}
+
+func containers(specs []corev1.Container, sts []corev1.ContainerStatus, init bool) []domain.ContainerDetail {
+	status := map[string]corev1.ContainerStatus{}
+	for _, s := range sts {
+		status[s.Name] = s
+	}
+	out := make([]domain.ContainerDetail, 0, len(specs))
+	for _, c := range specs {
+		st := status[c.Name]
+		out = append(out, domain.ContainerDetail{
+			Name:        c.Name,
+			Image:       c.Image,
+			Init:        init,
+			CPUReqm:     int(c.Resources.Requests.Cpu().MilliValue()),
+			CPULimm:     int(c.Resources.Limits.Cpu().MilliValue()),
+			MemReqBytes: c.Resources.Requests.Memory().Value(),
+			MemLimBytes: c.Resources.Limits.Memory().Value(),
+			Ready:       st.Ready,
+			State:       stateOf(st.State),
+			Restarts:    int(st.RestartCount),
+			LastExit:    lastExit(st.LastTerminationState),
+			Liveness:    probeOf(c.LivenessProbe),
+			Readiness:   probeOf(c.ReadinessProbe),
+			Startup:     probeOf(c.StartupProbe),
+		})
+	}
+	return out
+}
+
+func stateOf(s corev1.ContainerState) string {
+	switch {
+	case s.Running != nil:
+		return "Running"
+	case s.Waiting != nil:
+		return strings.TrimSuffix("Waiting: "+s.Waiting.Reason, ": ")
+	case s.Terminated != nil:
+		return strings.TrimSuffix("Terminated: "+s.Terminated.Reason, ": ")
+	}
+	return "Unknown"
+}
+
+func lastExit(s corev1.ContainerState) string {
+	if s.Terminated == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s (%d)", s.Terminated.Reason, s.Terminated.ExitCode)
+}
+
+// probeOf is a probe in one line: "http-get :8080/healthz every 10s".
+func probeOf(p *corev1.Probe) string {
+	if p == nil {
+		return ""
+	}
+	var how string
+	switch h := p.ProbeHandler; {
+	case h.HTTPGet != nil:
+		how = fmt.Sprintf("http-get :%s%s", h.HTTPGet.Port.String(), h.HTTPGet.Path)
+	case h.TCPSocket != nil:
+		how = "tcp :" + h.TCPSocket.Port.String()
+	case h.GRPC != nil:
+		how = fmt.Sprintf("grpc :%d", h.GRPC.Port)
+	case h.Exec != nil:
+		how = "exec " + strings.Join(h.Exec.Command, " ")
+	}
+	return fmt.Sprintf("%s every %ds", how, max(1, p.PeriodSeconds))
+}
+
+func (r *Repo) DescribeNode(ctx context.Context, name string) (domain.NodeDetail, error) {
+	n, err := r.core.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return domain.NodeDetail{}, err
+	}
+	info := n.Status.NodeInfo
+	d := domain.NodeDetail{
+		Capacity:      resources(n.Status.Capacity),
+		Allocatable:   resources(n.Status.Allocatable),
+		Labels:        n.Labels,
+		Unschedulable: n.Spec.Unschedulable,
+		OS:            info.OSImage,
+		Kernel:        info.KernelVersion,
+		Runtime:       info.ContainerRuntimeVersion,
+		Kubelet:       info.KubeletVersion,
+		Created:       n.CreationTimestamp.Time,
+	}
+	for _, c := range n.Status.Conditions {
+		d.Conditions = append(d.Conditions, domain.Condition{
+			Type: string(c.Type), Status: string(c.Status), Reason: c.Reason, Message: c.Message, Since: c.LastTransitionTime.Time,
+		})
+	}
+	for _, t := range n.Spec.Taints {
+		d.Taints = append(d.Taints, t.ToString())
+	}
+	for _, a := range n.Status.Addresses {
+		d.Addresses = append(d.Addresses, string(a.Type)+" "+a.Address)
+	}
+	d.Events = r.events(ctx, "", "Node", name)
+	return d, nil
+}
+
+func resources(l corev1.ResourceList) domain.Resources {
+	return domain.Resources{
+		CPUm:      int(l.Cpu().MilliValue()),
+		MemBytes:  l.Memory().Value(),
+		Pods:      int(l.Pods().Value()),
+		DiskBytes: l.StorageEphemeral().Value(),
+	}
+}
+
+// events are the object's events, oldest first; none when they can't be
+// listed.
+func (r *Repo) events(ctx context.Context, ns, kind, name string) []domain.Event {
+	list, err := r.core.CoreV1().Events(ns).List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.kind=%s,involvedObject.name=%s", kind, name),
+	})
+	if err != nil {
+		return nil
+	}
+	out := make([]domain.Event, 0, len(list.Items))
+	for _, e := range list.Items {
+		out = append(out, event(e))
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Last.Before(out[j].Last) })
+	return out
+}
+
+func event(e corev1.Event) domain.Event {
+	last := e.LastTimestamp.Time
+	if last.IsZero() {
+		last = e.EventTime.Time
+	}
+	return domain.Event{
+		Type: e.Type, Reason: e.Reason, Message: e.Message, Count: int(max(1, e.Count)), Last: last,
+		Namespace: e.InvolvedObject.Namespace,
+		Object:    e.InvolvedObject.Kind + "/" + e.InvolvedObject.Name,
+	}
+}
+
+// -------- Warnings --------
+
+func (r *Repo) ListWarnings(ctx context.Context, ns string) ([]domain.Event, error) {
+	if ns == "all" {
+		ns = ""
+	}
+	list, err := r.core.CoreV1().Events(ns).List(ctx, metav1.ListOptions{FieldSelector: "type=Warning"})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.Event, 0, len(list.Items))
+	for _, e := range list.Items {
+		out = append(out, event(e))
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Last.After(out[j].Last) })
+	return out, nil
+}
+
+// -------- Manifests --------
+
+func (r *Repo) Manifest(ctx context.Context, kind, ns, name string) ([]byte, error) {
+	var obj runtime.Object
+	switch kind {
+	case "Pod":
+		p, err := r.core.CoreV1().Pods(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		p.APIVersion, p.Kind = "v1", kind // typed gets leave TypeMeta empty
+		obj = p
+	case "Node":
+		n, err := r.core.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		n.APIVersion, n.Kind = "v1", kind
+		obj = n
+	default:
+		return nil, fmt.Errorf("no manifest for kind %q", kind)
+	}
+	return yaml.Marshal(obj)
+}
@@ -0,0 +1,271 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+	"github.com/HaPhanBaoMinh/kmet/internal/logs"
+)
+
+// Node logs come from the kubelet through the API server proxy:
+//
+//	/api/v1/nodes/<node>/proxy/logs/?query=kubelet   node log query (NodeLogQuery gate)
+//	/api/v1/nodes/<node>/proxy/logs/<file>           legacy /var/log file access
+//
+// Neither can follow, so both are polled.
+
+const nodeLogPoll = 2 * time.Second
+
+// defaultNodeServices are always offered; most nodes run at least one runtime.
+var defaultNodeServices = []string{"kubelet", "containerd", "crio", "docker", "kube-proxy"}
+
+var errNodeLogQueryOff = errors.New("node log query is not enabled on this node (NodeLogQuery feature gate / enableSystemLogQuery)")
+
+// NodeLogServices lists journal units plus whatever the kubelet's /var/log
+// listing exposes.
+func (r *Repo) NodeLogServices(ctx context.Context, node string) ([]string, error) {
+	out := append([]string(nil), defaultNodeServices...)
+	body, err := r.nodeProxy(node, "logs/").DoRaw(ctx)
+	if err != nil {
+		return out, nil
+	}
+	var files []string
+	for _, m := range hrefRe.FindAllStringSubmatch(string(body), -1) {
+		f := strings.TrimPrefix(m[1], "./")
+		if f == "" || strings.HasSuffix(f, "/") || strings.HasPrefix(f, "?") || strings.HasPrefix(f, "..") {
+			continue
+		}
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	return append(out, files...), nil
+}
+
+var hrefRe = regexp.MustCompile(`href="([^"]+)"`)
+
+func (r *Repo) nodeProxy(node, suffix string) *rest.Request {
+	return r.core.CoreV1().RESTClient().Get().
+		Resource("nodes").Name(node).SubResource("proxy").Suffix(suffix)
+}
+
+// streamNodeLogs polls the node log query API; if the kubelet doesn't
+// support it, files fall back to legacy access and everything else to events.
+func (r *Repo) streamNodeLogs(ctx context.Context, out chan<- domain.LogLine, t domain.LogsTarget) {
+	defer close(out)
+	send := logs.NewSender(out)
+	src := t.Name + "/" + t.Service
+
+	err := r.pollNodeQuery(ctx, send, t, src)
+	if err == nil || ctx.Err() != nil {
+		return
+	}
+	if isLogFile(t.Service) {
+		if err = r.pollNodeFile(ctx, send, t, src); err == nil || ctx.Err() != nil {
+			return
+		}
+	}
+	send.Send(ctx.Done(), domain.LogLine{
+		Time: time.Now(), Level: "WARN", Source: src,
+		Text: fmt.Sprintf("%v; showing node events instead", err),
+	})
+	r.watchNodeEvents(ctx, send, t.Name)
+}
+
+func (r *Repo) pollNodeQuery(ctx context.Context, send *logs.Sender, t domain.LogsTarget, src string) error {
+	var (
+		since time.Time
+		seen  = map[string]bool{} // raw lines at exactly `since`, already sent
+		first = true
+	)
+	switch {
+	case !t.SinceTime.IsZero():
+		since = t.SinceTime
+	case t.SinceSeconds > 0:
+		since = time.Now().Add(-time.Duration(t.SinceSeconds) * time.Second)
+	}
+	for {
+		req := r.nodeProxy(t.Name, "logs/").Param("query", t.Service)
+		if !since.IsZero() {
+			req = req.Param("sinceTime", since.UTC().Format(time.RFC3339))
+		}
+		if first && t.TailLines > 0 {
+			req = req.Param("tailLines", strconv.FormatInt(t.TailLines, 10))
+		}
+		body, err := req.DoRaw(ctx)
+		switch {
+		case err != nil && first:
+			return fmt.Errorf("node log query: %w", err)
+		case err == nil && first && looksLikeListing(body):
+			// a kubelet without the gate ignores ?query and lists /var/log
+			return errNodeLogQueryOff
+		case err == nil:
+			newest := since
+			var newSeen map[string]bool
+			for _, raw := range strings.Split(strings.TrimRight(string(body), "\n"), "\n") {
+				if raw == "" || strings.HasPrefix(raw, "-- ") { // journalctl banners
+					continue
+				}
+				// since follows the journal's clock: the time a parser
+				// pulls out of the message can be in any zone
+				at, text := journalStamp(raw)
+				if at.Before(since) || (at.Equal(since) && seen[raw]) {
+					continue
+				}
+				if at.After(newest) {
+					newest, newSeen = at, map[string]bool{}
+				}
+				if at.Equal(newest) && newSeen != nil {
+					newSeen[raw] = true
+				}
+				if !send.Send(ctx.Done(), stampedLine(at, text, src)) {
+					return nil
+				}
+			}
+			if newSeen != nil {
+				since, seen = newest, newSeen
+			} else if since.IsZero() {
+				since = time.Now()
+			}
+		}
+		first = false
+		if !sleepCtx(ctx, nodeLogPoll) {
+			return nil
+		}
+	}
+}
+
+// pollNodeFile reads a /var/log file through the legacy endpoint, which
+// always returns the whole file; only the growth since the last poll is sent.
+func (r *Repo) pollNodeFile(ctx context.Context, send *logs.Sender, t domain.LogsTarget, src string) error {
+	offset := -1
+	for {
+		body, err := r.nodeProxy(t.Name, "logs/"+strings.TrimPrefix(t.Service, "/")).DoRaw(ctx)
+		if err != nil && offset < 0 {
+			return fmt.Errorf("node log file %s: %w", t.Service, err)
+		}
+		if err == nil {
+			text := string(body)
+			switch {
+			case offset < 0: // first read: honour the tail
+				lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+				tail := int(t.TailLines)
+				if tail <= 0 {
+					tail = 500
+				}
+				if len(lines) > tail {
+					lines = lines[len(lines)-tail:]
+				}
+				for _, raw := range lines {
+					if raw != "" && !send.Send(ctx.Done(), journalLine(raw, src)) {
+						return nil
+					}
+				}
+			case len(text) < offset: // rotated or truncated
+				offset = 0
+				fallthrough
+			default:
+				for _, raw := range strings.Split(strings.TrimRight(text[offset:], "\n"), "\n") {
+					if raw != "" && !send.Send(ctx.Done(), journalLine(raw, src)) {
+						return nil
+					}
+				}
+			}
+			offset = len(text)
+		}
+		if !sleepCtx(ctx, nodeLogPoll) {
+			return nil
+		}
+	}
+}
+
+func (r *Repo) watchNodeEvents(ctx context.Context, send *logs.Sender, node string) {
+	w, err := r.core.CoreV1().Events("").Watch(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.kind=Node,involvedObject.name=%s", node),
+	})
+	if err != nil {
+		return
+	}
+	defer w.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-w.ResultChan():
+			if !ok {
+				return
+			}
+			if e, ok := ev.Object.(*corev1.Event); ok {
+				ln := domain.LogLine{Time: e.LastTimestamp.Time, Level: logs.NormalizeLevel(e.Type), Text: e.Message, Source: "event/" + node}
+				if !send.Send(ctx.Done(), ln) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// journalctl --output=short-precise, which the kubelet uses for queries:
+// "Jan 02 15:04:05.000000 host unit[pid]: message"
+var journalRe = regexp.MustCompile(`^([A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2}(?:\.\d+)?) \S+ [^:\s]+?(?:\[\d+\])?: (.*)$`)
+
+func journalLine(raw, src string) domain.LogLine {
+	at, text := journalStamp(raw)
+	return stampedLine(at, text, src)
+}
+
+// journalStamp splits the journal's (or a leading RFC3339) timestamp off a
+// line; a line without one is stamped now.
+func journalStamp(raw string) (time.Time, string) {
+	raw = strings.TrimRight(raw, "\r")
+	var (
+		at   time.Time
+		text string
+	)
+	if m := journalRe.FindStringSubmatch(raw); m != nil {
+		if ts, err := time.Parse("Jan _2 15:04:05.000000 2006", m[1]+fracPad(m[1])+" "+strconv.Itoa(time.Now().Year())); err == nil {
+			at = ts.Local()
+		}
+		text = m[2]
+	} else {
+		at, text = splitTimestamp(raw)
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+	return at, text
+}
+
+func stampedLine(at time.Time, text, src string) domain.LogLine {
+	ln := domain.LogLine{Time: at, Text: text, Source: src}
+	logs.Parse(&ln)
+	return ln
+}
+
+// fracPad makes a journal timestamp match the 6-digit layout.
+func fracPad(ts string) string {
+	i := strings.IndexByte(ts, '.')
+	if i < 0 {
+		return ".000000"
+	}
+	return strings.Repeat("0", max(0, 6-(len(ts)-i-1)))
+}
+
+func looksLikeListing(body []byte) bool {
+	s := strings.TrimSpace(string(body))
+	return strings.HasPrefix(s, "<pre>") || strings.HasPrefix(s, "<!doctype") || strings.HasPrefix(s, "<html")
+}
+
+func isLogFile(svc string) bool {
+	return strings.ContainsAny(svc, "./")
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -17,14 +18,16 @@ import (
 	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
 
 	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+	"github.com/HaPhanBaoMinh/kmet/internal/logs"
 )
 
 type Repo struct {
 	core    *kubernetes.Clientset
 	metrics *metricsclient.Clientset
 
-	podTrend  map[string][]float64
-	nodeTrend map[string][]float64
+	mu        sync.Mutex // trends: listings can run concurrently
+	podTrend  map[string]domain.Trend
+	nodeTrend map[string]domain.Trend
 }
 
 func New(kubeconfigPath, contextName string) (*Repo, error) {
@@ -44,8 +47,8 @@ func New(kubeconfigPath, contextName string) (*Repo, error) {
 	}
 	return &Repo{
 		core: core, metrics: m,
-		podTrend:  make(map[string][]float64),
-		nodeTrend: make(map[string][]float64),
+		podTrend:  make(map[string]domain.Trend),
+		nodeTrend: make(map[string]domain.Trend),
 	}, nil
 }
 
@@ -61,6 +64,20 @@ func loadRESTConfig(kubeconfigPath, contextName string) (*rest.Config, error) {
 	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
 }
 
+// CurrentContext is the kubeconfig context New connects with: contextName
+// if set, else the kubeconfig's current-context ("" if it can't be read).
+func CurrentContext(kubeconfigPath, contextName string) string {
+	if contextName != "" {
+		return contextName
+	}
+	loadingRules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPath}
+	raw, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return ""
+	}
+	return raw.CurrentContext
+}
+
 // -------- MetricsRepo --------
 
 func (r *Repo) ListNamespaces(ctx context.Context) ([]string, error) {
@@ -77,10 +94,10 @@ func (r *Repo) ListNamespaces(ctx context.Context) ([]string, error) {
 	return out, nil
 }
 
-func (r *Repo) ListPods(ctx context.Context, ns string, selector string) ([]domain.PodMetric, error) {
-	opts := metav1.ListOptions{}
-	if selector != "" {
-		opts.LabelSelector = selector // k8s-standard label selector string
+func (r *Repo) ListPods(ctx context.Context, ns string, sel domain.Selector) ([]domain.PodMetric, error) {
+	opts := metav1.ListOptions{
+		LabelSelector: sel.Labels, // k8s-standard selector strings
+		FieldSelector: sel.Fields,
 	}
 
 	// if ns "all" -> empty string
@@ -95,6 +112,8 @@ func (r *Repo) ListPods(ctx context.Context, ns string, selector string) ([]doma
 	}
 
 	// 2) Get pod usage (metrics.k8s.io). Gracefully degrade if unavailable.
+	// Pod fields (phase, node) aren't selectable there; usage of pods the
+	// field selector dropped is simply never looked up.
 	pms, err := r.metrics.MetricsV1beta1().PodMetricses(ns).List(ctx, metav1.ListOptions{
 		LabelSelector: opts.LabelSelector,
 	})
@@ -119,6 +138,17 @@ func (r *Repo) ListPods(ctx context.Context, ns string, selector string) ([]doma
 		podUsage[m.Namespace+"/"+m.Name] = total
 	}
 
+	// 3) Node allocatable, for "% of node", and pressure. One list instead
+	// of a get per pod.
+	alloc := map[string]corev1.ResourceList{}
+	pressures := map[string][]string{}
+	if nodes, err := r.core.CoreV1().Nodes().List(ctx, metav1.ListOptions{}); err == nil {
+		for i, n := range nodes.Items {
+			alloc[n.Name] = n.Status.Allocatable
+			pressures[n.Name], _ = conditions(&nodes.Items[i])
+		}
+	}
+
 	out := make([]domain.PodMetric, 0, len(pods.Items))
 	for _, p := range pods.Items {
 		key := p.Namespace + "/" + p.Name
@@ -146,32 +176,44 @@ func (r *Repo) ListPods(ctx context.Context, ns string, selector string) ([]doma
 			ctr = p.Spec.Containers[0].Name
 		}
 
-		// Requests of the first container (simple; you can sum across containers later)
-		var cpuReqm int
-		var memReqBytes int64
+		// Requests and limits of the first container (simple; you can sum across containers later)
+		var cpuReqm, cpuLimm int
+		var memReqBytes, memLimBytes int64
 		if len(p.Spec.Containers) > 0 {
-			req := p.Spec.Containers[0].Resources.Requests
-			if cpuQ := req.Cpu(); cpuQ != nil {
-				cpuReqm = int(cpuQ.MilliValue())
-			}
-			if memQ := req.Memory(); memQ != nil {
-				memReqBytes = memQ.Value()
-			}
+			res := p.Spec.Containers[0].Resources
+			cpuReqm = int(res.Requests.Cpu().MilliValue())
+			memReqBytes = res.Requests.Memory().Value()
+			cpuLimm = int(res.Limits.Cpu().MilliValue())
+			memLimBytes = res.Limits.Memory().Value()
 		}
+		nodeAlloc := alloc[p.Spec.NodeName]
+
+		ownerKind, ownerName := ownerOf(&p)
 
 		pm := domain.PodMetric{
-			Namespace:   p.Namespace,
-			PodName:     p.Name,
-			Container:   ctr,
-			NodeName:    p.Spec.NodeName,
-			CPUm:        int(cpuMil),
-			MemBytes:    memB,
-			CPUReqm:     cpuReqm,
-			MemReqBytes: memReqBytes,
-			Ready:       ready,
-			Phase:       string(p.Status.Phase),
-			CPUTrend:    r.appendTrend(r.podTrend, key, normCPU(cpuMil)),
-			MemTrend:    r.appendTrend(r.podTrend, key+"-mem", normMem(memB)),
+			Namespace:    p.Namespace,
+			PodName:      p.Name,
+			Container:    ctr,
+			NodeName:     p.Spec.NodeName,
+			CPUm:         int(cpuMil),
+			MemBytes:     memB,
+			CPUReqm:      cpuReqm,
+			MemReqBytes:  memReqBytes,
+			CPULimm:      cpuLimm,
+			MemLimBytes:  memLimBytes,
+			QoS:          string(p.Status.QOSClass),
+			Labels:       p.Labels,
+			NodeCPUm:     int(nodeAlloc.Cpu().MilliValue()),
+			NodeMemBytes: nodeAlloc.Memory().Value(),
+			NodePressure: pressures[p.Spec.NodeName],
+			Ready:        ready,
+			Phase:        string(p.Status.Phase),
+			Restarts:     restarts(p.Status.ContainerStatuses),
+			Created:      p.CreationTimestamp.Time,
+			OwnerKind:    ownerKind,
+			OwnerName:    ownerName,
+			CPUTrend:     r.appendTrend(r.podTrend, key, normCPU(cpuMil), float64(cpuMil)),
+			MemTrend:     r.appendTrend(r.podTrend, key+"-mem", normMem(memB), float64(memB)),
 		}
 		out = append(out, pm)
 	}
@@ -179,6 +221,30 @@ func (r *Repo) ListPods(ctx context.Context, ns string, selector string) ([]doma
 	return out, nil
 }
 
+// ownerOf returns the workload that manages p. ReplicaSets created by a
+// Deployment are reported as the Deployment (name minus pod-template-hash)
+// so logs follow rollouts.
+func ownerOf(p *corev1.Pod) (kind, name string) {
+	ref := metav1.GetControllerOf(p)
+	if ref == nil {
+		return "", ""
+	}
+	if ref.Kind == "ReplicaSet" {
+		if h := p.Labels["pod-template-hash"]; h != "" && strings.HasSuffix(ref.Name, "-"+h) {
+			return "Deployment", strings.TrimSuffix(ref.Name, "-"+h)
+		}
+	}
+	return ref.Kind, ref.Name
+}
+
+func restarts(sts []corev1.ContainerStatus) int {
+	n := 0
+	for _, s := range sts {
+		n += int(s.RestartCount)
+	}
+	return n
+}
+
 func readyStr(sts []corev1.ContainerStatus) string {
 	r, t := 0, len(sts)
 	for _, s := range sts {
@@ -214,14 +280,34 @@ func normMem(b int64) float64 {
 	return v
 }
 
-func (r *Repo) appendTrend(store map[string][]float64, key string, v float64) domain.Trend {
-	s := store[key]
-	s = append(s, v)
-	if len(s) > 90 {
-		s = s[len(s)-90:]
-	} // 90 samples ~ 90 ticks
-	store[key] = s
-	return domain.Trend{Samples: s, Window: time.Minute}
+// appendTrend adds a sample (normalized and in its unit) to the key's
+// trend, keeping the last 90 (~90 ticks). A sample less than a second after
+// the last one replaces it, so a second listing of the same pods (the node
+// detail view) doesn't squeeze the time axis.
+func (r *Repo) appendTrend(store map[string]domain.Trend, key string, norm, value float64) domain.Trend {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := store[key]
+	if n := len(t.Times); n > 0 && time.Since(t.Times[n-1]) < time.Second {
+		t.Samples, t.Values, t.Times = t.Samples[:n-1], t.Values[:n-1], t.Times[:n-1]
+	}
+	t.Samples = keepLast(append(t.Samples, norm), 90)
+	t.Values = keepLast(append(t.Values, value), 90)
+	t.Times = keepLast(append(t.Times, time.Now()), 90)
+	t.Window = time.Minute
+	store[key] = t
+	// copies: the stored slices keep growing under the caller
+	t.Samples = append([]float64(nil), t.Samples...)
+	t.Values = append([]float64(nil), t.Values...)
+	t.Times = append([]time.Time(nil), t.Times...)
+	return t
+}
+
+func keepLast[T any](s []T, n int) []T {
+	if len(s) > n {
+		return s[len(s)-n:]
+	}
+	return s
 }
 func (r *Repo) ListNodes(ctx context.Context) ([]domain.NodeMetric, error) {
 	// 1) Pull node usage from metrics.k8s.io; gracefully degrade if unavailable.
@@ -266,20 +352,31 @@ func (r *Repo) ListNodes(ctx context.Context) ([]domain.NodeMetric, error) {
 		// (Optional) Count actual pods on this node (one API call per node).
 		// For large clusters, consider precomputing once outside the loop.
 		podCount := 0
+		var cpuReq, memReq int64
 		if podsOnNode, err := r.core.CoreV1().Pods("").List(ctx, metav1.ListOptions{
 			FieldSelector: "spec.nodeName=" + n.Name,
 		}); err == nil {
 			podCount = len(podsOnNode.Items)
+			cpuReq, memReq = requests(podsOnNode.Items)
 		}
 
+		pressure, notReady := conditions(&n)
 		nm := domain.NodeMetric{
 			NodeName: n.Name,
 			CPUUsed:  clamp01(uCPU),
 			MEMUsed:  clamp01(uMem),
 			Pods:     podCount, // was: capacity-allocatable; now actual running/pending pods count
 			K8sVer:   n.Status.NodeInfo.KubeletVersion,
-			CPUTrend: r.appendTrend(r.nodeTrend, "cpu-"+n.Name, clamp01(uCPU)),
-			MEMTrend: r.appendTrend(r.nodeTrend, "mem-"+n.Name, clamp01(uMem)),
+			CPUTrend: r.appendTrend(r.nodeTrend, "cpu-"+n.Name, clamp01(uCPU), uCPU),
+			MEMTrend: r.appendTrend(r.nodeTrend, "mem-"+n.Name, clamp01(uMem), uMem),
+			Pressure: pressure,
+			NotReady: notReady,
+			Labels:   n.Labels,
+
+			CPUAllocm:     int(allocCPU),
+			MemAllocBytes: allocMem,
+			CPUReqm:       int(cpuReq),
+			MemReqBytes:   memReq,
 		}
 		out = append(out, nm)
 	}
@@ -287,6 +384,38 @@ func (r *Repo) ListNodes(ctx context.Context) ([]domain.NodeMetric, error) {
 	return out, nil
 }
 
+// requests sums the cpu (millicores) and memory requests of the pods that
+// still hold them: finished pods give theirs back.
+func requests(pods []corev1.Pod) (cpuMil, memBytes int64) {
+	for _, p := range pods {
+		if p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		for _, c := range p.Spec.Containers {
+			cpuMil += c.Resources.Requests.Cpu().MilliValue()
+			memBytes += c.Resources.Requests.Memory().Value()
+		}
+	}
+	return cpuMil, memBytes
+}
+
+// conditions returns the node's pressure conditions that are True and
+// whether it is not Ready.
+func conditions(n *corev1.Node) (pressure []string, notReady bool) {
+	notReady = true
+	for _, c := range n.Status.Conditions {
+		switch c.Type {
+		case corev1.NodeReady:
+			notReady = c.Status != corev1.ConditionTrue
+		case corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure:
+			if c.Status == corev1.ConditionTrue {
+				pressure = append(pressure, string(c.Type))
+			}
+		}
+	}
+	return pressure, notReady
+}
+
 func clamp01(v float64) float64 {
 	if v < 0 {
 		return 0
@@ -305,17 +434,16 @@ func max64(a, b int64) int64 {
 
 // -------- LogsRepo --------
 
+// logBuffer is the per-stream channel capacity; past it lines get dropped
+// (see logs.Sender) rather than stalling the kubelet connection.
+const logBuffer = 1000
+
 func (r *Repo) StreamLogs(ctx context.Context, t domain.LogsTarget) (<-chan domain.LogLine, error) {
-	ch := make(chan domain.LogLine, 200)
+	ch := make(chan domain.LogLine, logBuffer)
 
 	switch t.Kind {
 	case "Pod":
-		req := r.core.CoreV1().Pods(t.Namespace).GetLogs(t.Name, &corev1.PodLogOptions{
-			Container:  t.Container,
-			Follow:     true,
-			Timestamps: false,
-			// SinceSeconds: pointer.Int64(600),
-		})
+		req := r.core.CoreV1().Pods(t.Namespace).GetLogs(t.Name, podLogOptions(t, t.Container))
 		stream, err := req.Stream(ctx)
 		if err != nil {
 			close(ch)
@@ -324,15 +452,12 @@ func (r *Repo) StreamLogs(ctx context.Context, t domain.LogsTarget) (<-chan doma
 		go func() {
 			defer close(ch)
 			defer stream.Close()
+			send := logs.NewSender(ch)
 			rd := bufio.NewReader(stream)
 			for {
 				line, err := rd.ReadString('\n')
-				if len(line) > 0 {
-					ch <- domain.LogLine{
-						Time: time.Now(), Level: levelFrom(line),
-						Text:   strings.TrimRight(line, "\r\n"),
-						Source: fmt.Sprintf("%s/%s", t.Name, t.Container),
-					}
+				if len(line) > 0 && !send.Send(ctx.Done(), logLine(line, fmt.Sprintf("%s/%s", t.Name, t.Container))) {
+					return
 				}
 				if err != nil {
 					if err == io.EOF || ctx.Err() != nil {
@@ -345,15 +470,25 @@ func (r *Repo) StreamLogs(ctx context.Context, t domain.LogsTarget) (<-chan doma
 		}()
 		return ch, nil
 
-	case "Deployment", "StatefulSet", "DaemonSet":
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job", "Selector":
 		ns := t.Namespace
-		sel, _ := r.selectorOfOwner(ctx, ns, t)
-		pods, _ := r.core.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{LabelSelector: sel})
-		go r.streamMultiPods(ctx, ch, pods.Items, "")
+		if ns == "all" {
+			ns = ""
+		}
+		sel, err := r.selectorOfOwner(ctx, ns, t)
+		if err != nil {
+			close(ch)
+			return nil, err
+		}
+		go r.tailSelector(ctx, ch, ns, sel, t)
 		return ch, nil
 
 	case "Node":
-		go r.streamNodeEvents(ctx, ch, t.Name)
+		if t.Service == "" {
+			go r.streamNodeEvents(ctx, ch, t.Name)
+		} else {
+			go r.streamNodeLogs(ctx, ch, t)
+		}
 		return ch, nil
 	}
 
@@ -362,68 +497,49 @@ func (r *Repo) StreamLogs(ctx context.Context, t domain.LogsTarget) (<-chan doma
 	return ch, nil
 }
 
-func (r *Repo) streamMultiPods(ctx context.Context, out chan<- domain.LogLine, pods []corev1.Pod, container string) {
-	for _, p := range pods {
-		req := r.core.CoreV1().Pods(p.Namespace).GetLogs(p.Name, &corev1.PodLogOptions{
-			Container: container,
-			Follow:    true,
-		})
-		stream, err := req.Stream(ctx)
-		if err != nil {
-			continue
-		}
-		go func(pod corev1.Pod, rc io.ReadCloser) {
-			defer rc.Close()
-			rd := bufio.NewReader(rc)
-			for {
-				line, err := rd.ReadString('\n')
-				if len(line) > 0 {
-					out <- domain.LogLine{Time: time.Now(), Level: levelFrom(line),
-						Text:   strings.TrimRight(line, "\r\n"),
-						Source: fmt.Sprintf("%s/%s", pod.Name, container)}
-				}
-				if err != nil {
-					return
-				}
-			}
-		}(p, stream)
-	}
+func (r *Repo) streamNodeEvents(ctx context.Context, out chan<- domain.LogLine, node string) {
+	defer close(out)
+	r.watchNodeEvents(ctx, logs.NewSender(out), node)
 }
 
-func (r *Repo) streamNodeEvents(ctx context.Context, out chan<- domain.LogLine, node string) {
-	w, err := r.core.CoreV1().Events("").Watch(ctx, metav1.ListOptions{
-		FieldSelector: fmt.Sprintf("involvedObject.kind=Node,involvedObject.name=%s", node),
-	})
-	if err != nil {
-		close(out)
-		return
+// podLogOptions maps the target's history range onto the API options. We
+// always ask for timestamps so backfilled lines carry their real time.
+func podLogOptions(t domain.LogsTarget, container string) *corev1.PodLogOptions {
+	o := &corev1.PodLogOptions{
+		Container:  container,
+		Follow:     !t.Previous, // a terminated instance has nothing to follow
+		Timestamps: true,
+		Previous:   t.Previous,
 	}
-	for ev := range w.ResultChan() {
-		if e, ok := ev.Object.(*corev1.Event); ok {
-			out <- domain.LogLine{Time: e.LastTimestamp.Time, Level: strings.ToUpper(string(e.Type)), Text: e.Message, Source: "event/" + node}
-		}
+	if t.TailLines > 0 {
+		n := t.TailLines
+		o.TailLines = &n
 	}
+	switch {
+	case !t.SinceTime.IsZero():
+		st := metav1.NewTime(t.SinceTime)
+		o.SinceTime = &st
+	case t.SinceSeconds > 0:
+		n := t.SinceSeconds
+		o.SinceSeconds = &n
+	}
+	return o
 }
 
-func (r *Repo) selectorOfOwner(ctx context.Context, ns string, t domain.LogsTarget) (string, error) {
-	if t.Kind == "Deployment" {
-		d, err := r.core.AppsV1().Deployments(ns).Get(ctx, t.Name, metav1.GetOptions{})
-		if err != nil {
-			return "", err
-		}
-		return metav1.FormatLabelSelector(d.Spec.Selector), nil
-	}
-	return "", fmt.Errorf("not implemented")
+// logLine turns a raw "<RFC3339Nano> text" line into a LogLine, falling
+// back to the local clock if the kubelet didn't prefix a timestamp.
+func logLine(raw, source string) domain.LogLine {
+	ts, text := splitTimestamp(strings.TrimRight(raw, "\r\n"))
+	ln := domain.LogLine{Time: ts, Text: text, Source: source}
+	logs.Parse(&ln)
+	return ln
 }
 
-func levelFrom(s string) string {
-	ss := strings.ToUpper(s)
-	switch {
-	case strings.Contains(ss, "ERROR"):
-		return "ERROR"
-	case strings.Contains(ss, "WARN"):
-		return "WARN"
-	default:
-		return "INFO"
+func splitTimestamp(line string) (time.Time, string) {
+	if i := strings.IndexByte(line, ' '); i > 0 {
+		if ts, err := time.Parse(time.RFC3339Nano, line[:i]); err == nil {
+			return ts.Local(), line[i+1:]
+		}
 	}
+	return time.Now(), line
 }
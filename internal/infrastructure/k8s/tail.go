@@ -0,0 +1,330 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+	"github.com/HaPhanBaoMinh/kmet/internal/logs"
+)
+
+// multiTail follows the logs of every container of every pod matching a
+// label selector, stern-style: pods joining the selector are picked up,
+// pods leaving it are dropped and restarted containers are re-attached.
+type multiTail struct {
+	r    *Repo
+	ns   string
+	sel  string
+	t    domain.LogsTarget
+	out  chan<- domain.LogLine
+	send *logs.Sender
+
+	mu     sync.Mutex
+	active map[string]*tailHandle // ns/pod/container -> running tail
+	tailed map[string]string      // ns/pod/container -> last containerID tailed
+	next   map[string]string      // ns/pod/container -> containerID to follow once the running tail ends
+	wg     sync.WaitGroup
+}
+
+func (r *Repo) tailSelector(ctx context.Context, out chan<- domain.LogLine, ns, sel string, t domain.LogsTarget) {
+	mt := &multiTail{
+		r: r, ns: ns, sel: sel, t: t, out: out, send: logs.NewSender(out),
+		active: map[string]*tailHandle{},
+		tailed: map[string]string{},
+		next:   map[string]string{},
+	}
+	mt.run(ctx)
+	mt.wg.Wait()
+	close(out)
+}
+
+// run lists then watches pods until ctx is done, re-listing whenever the
+// watch is closed by the server.
+func (mt *multiTail) run(ctx context.Context) {
+	pods := mt.r.core.CoreV1().Pods(mt.ns)
+	for ctx.Err() == nil {
+		list, err := pods.List(ctx, metav1.ListOptions{LabelSelector: mt.sel})
+		if err != nil {
+			if !sleepCtx(ctx, 2*time.Second) {
+				return
+			}
+			continue
+		}
+		seen := map[string]bool{}
+		for i := range list.Items {
+			seen[list.Items[i].Namespace+"/"+list.Items[i].Name] = true
+			mt.sync(ctx, &list.Items[i])
+		}
+		mt.dropMissing(seen)
+
+		w, err := pods.Watch(ctx, metav1.ListOptions{
+			LabelSelector:   mt.sel,
+			ResourceVersion: list.ResourceVersion,
+		})
+		if err != nil {
+			if !sleepCtx(ctx, 2*time.Second) {
+				return
+			}
+			continue
+		}
+		mt.consume(ctx, w)
+		w.Stop()
+	}
+}
+
+// consume applies watch events until the watch closes or ctx is done.
+func (mt *multiTail) consume(ctx context.Context, w watch.Interface) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-w.ResultChan():
+			if !ok {
+				return
+			}
+			p, ok := ev.Object.(*corev1.Pod)
+			if !ok {
+				continue
+			}
+			switch ev.Type {
+			case watch.Added, watch.Modified:
+				mt.sync(ctx, p)
+			case watch.Deleted:
+				mt.dropPod(p.Namespace, p.Name)
+			}
+		}
+	}
+}
+
+// sync starts a tail for each started container instance we aren't
+// following yet. A new containerID means the container restarted; the
+// restart can be seen before the old instance's stream has ended, so then
+// the running tail drains it and moves on to the new one.
+func (mt *multiTail) sync(ctx context.Context, p *corev1.Pod) {
+	for _, c := range podContainers(p) {
+		if mt.t.Container != "" && c.name != mt.t.Container {
+			continue
+		}
+		if c.id == "" {
+			continue // not started yet
+		}
+		key := p.Namespace + "/" + p.Name + "/" + c.name
+
+		mt.mu.Lock()
+		prev, seen := mt.tailed[key]
+		_, running := mt.active[key]
+		switch {
+		case prev == c.id:
+		case running:
+			mt.next[key] = c.id
+		default:
+			mt.start(ctx, key, p.Namespace, p.Name, c.name, c.id, seen)
+		}
+		mt.mu.Unlock()
+	}
+}
+
+// start tails a container instance; mu must be held. The first attach
+// honours the requested range; a restarted instance is read from its
+// beginning so nothing between the two is lost.
+func (mt *multiTail) start(ctx context.Context, key, ns, pod, container, id string, restarted bool) {
+	opts := podLogOptions(mt.t, container)
+	if restarted {
+		opts = &corev1.PodLogOptions{Container: container, Follow: true, Timestamps: true}
+	}
+	tctx, cancel := context.WithCancel(ctx)
+	h := &tailHandle{cancel}
+	mt.active[key] = h
+	mt.tailed[key] = id
+	mt.wg.Add(1)
+	go mt.tail(ctx, tctx, h, key, ns, pod, opts)
+}
+
+// tail follows one stream until it ends or ctx is done, then starts the
+// instance sync queued for its key, if any, under the parent context. A
+// stream that can't be opened (container still creating, apiserver hiccup)
+// is retried with backoff until it opens or a newer instance is queued.
+func (mt *multiTail) tail(parent, ctx context.Context, h *tailHandle, key, ns, pod string, opts *corev1.PodLogOptions) {
+	defer mt.wg.Done()
+	defer func() {
+		h.cancel()
+		mt.mu.Lock()
+		defer mt.mu.Unlock()
+		if mt.active[key] != h { // dropped, or a recreated pod owns the key
+			return
+		}
+		delete(mt.active, key)
+		if id, ok := mt.next[key]; ok && parent.Err() == nil {
+			delete(mt.next, key)
+			mt.start(parent, key, ns, pod, opts.Container, id, true)
+		}
+	}()
+
+	req := mt.r.core.CoreV1().Pods(ns).GetLogs(pod, opts)
+	var stream io.ReadCloser
+	for delay := time.Second; ; delay = min(2*delay, tailRetryMax) {
+		var err error
+		if stream, err = req.Stream(ctx); err == nil {
+			break
+		}
+		mt.mu.Lock()
+		_, queued := mt.next[key]
+		mt.mu.Unlock()
+		if queued || !sleepCtx(ctx, delay) {
+			return
+		}
+	}
+	defer stream.Close()
+	src := fmt.Sprintf("%s/%s", pod, opts.Container)
+	rd := bufio.NewReader(stream)
+	for {
+		line, err := rd.ReadString('\n')
+		if len(line) > 0 && !mt.send.Send(ctx.Done(), logLine(line, src)) {
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (mt *multiTail) dropPod(ns, pod string) {
+	prefix := ns + "/" + pod + "/"
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+	for key, h := range mt.active {
+		if strings.HasPrefix(key, prefix) {
+			h.cancel()
+			delete(mt.active, key)
+		}
+	}
+	for key := range mt.tailed {
+		if strings.HasPrefix(key, prefix) {
+			delete(mt.tailed, key)
+			delete(mt.next, key)
+		}
+	}
+}
+
+// dropMissing stops tails for pods that vanished while we weren't watching;
+// seen is keyed by ns/pod.
+func (mt *multiTail) dropMissing(seen map[string]bool) {
+	mt.mu.Lock()
+	var gone [][2]string
+	for key := range mt.tailed {
+		ns, rest, _ := strings.Cut(key, "/")
+		pod, _, _ := strings.Cut(rest, "/")
+		if !seen[ns+"/"+pod] {
+			gone = append(gone, [2]string{ns, pod})
+		}
+	}
+	mt.mu.Unlock()
+	for _, g := range gone {
+		mt.dropPod(g[0], g[1])
+	}
+}
+
+// tailRetryMax caps the backoff between attempts to open a stream.
+const tailRetryMax = 30 * time.Second
+
+type tailHandle struct{ cancel context.CancelFunc }
+
+type containerRef struct {
+	name string
+	id   string // containerID of the current instance, "" until started
+}
+
+// podContainers lists init, regular and ephemeral containers in that order.
+func podContainers(p *corev1.Pod) []containerRef {
+	var out []containerRef
+	add := func(names []string, sts []corev1.ContainerStatus) {
+		ids := map[string]string{}
+		for _, s := range sts {
+			if s.State.Running != nil || s.State.Terminated != nil {
+				ids[s.Name] = s.ContainerID
+			}
+		}
+		for _, n := range names {
+			out = append(out, containerRef{name: n, id: ids[n]})
+		}
+	}
+	var names []string
+	for _, c := range p.Spec.InitContainers {
+		names = append(names, c.Name)
+	}
+	add(names, p.Status.InitContainerStatuses)
+	names = names[:0]
+	for _, c := range p.Spec.Containers {
+		names = append(names, c.Name)
+	}
+	add(names, p.Status.ContainerStatuses)
+	names = names[:0]
+	for _, c := range p.Spec.EphemeralContainers {
+		names = append(names, c.Name)
+	}
+	add(names, p.Status.EphemeralContainerStatuses)
+	return out
+}
+
+// selectorOfOwner resolves the label selector of a workload.
+func (r *Repo) selectorOfOwner(ctx context.Context, ns string, t domain.LogsTarget) (string, error) {
+	var sel *metav1.LabelSelector
+	switch t.Kind {
+	case "Deployment":
+		d, err := r.core.AppsV1().Deployments(ns).Get(ctx, t.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		sel = d.Spec.Selector
+	case "StatefulSet":
+		s, err := r.core.AppsV1().StatefulSets(ns).Get(ctx, t.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		sel = s.Spec.Selector
+	case "DaemonSet":
+		d, err := r.core.AppsV1().DaemonSets(ns).Get(ctx, t.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		sel = d.Spec.Selector
+	case "ReplicaSet":
+		rs, err := r.core.AppsV1().ReplicaSets(ns).Get(ctx, t.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		sel = rs.Spec.Selector
+	case "Job":
+		j, err := r.core.BatchV1().Jobs(ns).Get(ctx, t.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		sel = j.Spec.Selector
+	case "Selector":
+		return t.Name, nil
+	default:
+		return "", fmt.Errorf("logs: unsupported kind %q", t.Kind)
+	}
+	if sel == nil {
+		return "", fmt.Errorf("logs: %s/%s has no selector", t.Kind, t.Name)
+	}
+	return metav1.FormatLabelSelector(sel), nil
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
@@ -0,0 +1,185 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+)
+
+// Manifest renders the mock objects as the API server would, managed
+// fields and status included, from the same data DescribePod and
+// DescribeNode make up.
+func (r *Repo) Manifest(ctx context.Context, kind, ns, name string) ([]byte, error) {
+	var obj runtime.Object
+	switch kind {
+	case "Pod":
+		d, err := r.DescribePod(ctx, ns, name)
+		if err != nil {
+			return nil, err
+		}
+		obj = mockPod(ns, name, d)
+	case "Node":
+		d, err := r.DescribeNode(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		obj = mockNode(name, d)
+	default:
+		return nil, fmt.Errorf("no manifest for kind %q", kind)
+	}
+	return yaml.Marshal(obj)
+}
+
+func mockPod(ns, name string, d domain.PodDetail) *corev1.Pod {
+	var mp = mockPods[0]
+	for _, p := range mockPods {
+		if p.name == name {
+			mp = p
+		}
+	}
+	hash := mp.labels["pod-template-hash"]
+	yes := true
+	p := &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: ns, Labels: mp.labels,
+			UID:               types.UID("5c0e6a1d-7f39-4d1c-9b7e-2f4c" + hash[:8]),
+			CreationTimestamp: metav1.NewTime(d.Started),
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1", Kind: "ReplicaSet", Name: mp.owner + "-" + hash,
+				UID:        types.UID("0b8f3e52-1c6a-4e07-a1d9-" + hash + "00"),
+				Controller: &yes, BlockOwnerDeletion: &yes,
+			}},
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "kube-controller-manager", Operation: metav1.ManagedFieldsOperationUpdate, APIVersion: "v1", Time: ptr(metav1.NewTime(d.Started)), FieldsType: "FieldsV1"},
+				{Manager: "kubelet", Operation: metav1.ManagedFieldsOperationUpdate, APIVersion: "v1", Time: ptr(metav1.NewTime(d.Started.Add(5 * time.Second))), FieldsType: "FieldsV1", Subresource: "status"},
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:           mp.node,
+			ServiceAccountName: d.ServiceAccount,
+			RestartPolicy:      corev1.RestartPolicyAlways,
+		},
+		Status: corev1.PodStatus{
+			Phase:     corev1.PodRunning,
+			PodIP:     d.IP,
+			QOSClass:  corev1.PodQOSClass(d.QoS),
+			StartTime: ptr(metav1.NewTime(d.Started)),
+		},
+	}
+	for _, c := range d.Containers {
+		ctr := corev1.Container{
+			Name: c.Name, Image: c.Image, ImagePullPolicy: corev1.PullIfNotPresent,
+			Resources: corev1.ResourceRequirements{
+				Requests: resourceList(c.CPUReqm, c.MemReqBytes),
+				Limits:   resourceList(c.CPULimm, c.MemLimBytes),
+			},
+			LivenessProbe:  mockProbe(c.Liveness),
+			ReadinessProbe: mockProbe(c.Readiness),
+		}
+		st := corev1.ContainerStatus{Name: c.Name, Image: c.Image, Ready: c.Ready, RestartCount: int32(c.Restarts)}
+		switch {
+		case c.State == "Running":
+			st.State.Running = &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(d.Started)}
+		case strings.HasPrefix(c.State, "Waiting: "):
+			st.State.Waiting = &corev1.ContainerStateWaiting{Reason: strings.TrimPrefix(c.State, "Waiting: ")}
+		case strings.HasPrefix(c.State, "Terminated: "):
+			st.State.Terminated = &corev1.ContainerStateTerminated{Reason: strings.TrimPrefix(c.State, "Terminated: ")}
+		}
+		if c.Init {
+			p.Spec.InitContainers = append(p.Spec.InitContainers, ctr)
+			p.Status.InitContainerStatuses = append(p.Status.InitContainerStatuses, st)
+		} else {
+			p.Spec.Containers = append(p.Spec.Containers, ctr)
+			p.Status.ContainerStatuses = append(p.Status.ContainerStatuses, st)
+		}
+	}
+	for _, c := range d.Conditions {
+		p.Status.Conditions = append(p.Status.Conditions, corev1.PodCondition{
+			Type: corev1.PodConditionType(c.Type), Status: corev1.ConditionStatus(c.Status), LastTransitionTime: metav1.NewTime(c.Since),
+		})
+	}
+	return p
+}
+
+func mockNode(name string, d domain.NodeDetail) *corev1.Node {
+	n := &corev1.Node{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name, Labels: d.Labels, CreationTimestamp: metav1.NewTime(d.Created),
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "kubelet", Operation: metav1.ManagedFieldsOperationUpdate, APIVersion: "v1", Time: ptr(metav1.NewTime(d.Created)), FieldsType: "FieldsV1"},
+			},
+		},
+		Spec: corev1.NodeSpec{ProviderID: "aws:///eu-west-1/i-0" + strings.ReplaceAll(name[3:], "-", "")},
+		Status: corev1.NodeStatus{
+			Capacity:    nodeResources(d.Capacity),
+			Allocatable: nodeResources(d.Allocatable),
+			NodeInfo: corev1.NodeSystemInfo{
+				OSImage: d.OS, KernelVersion: d.Kernel, ContainerRuntimeVersion: d.Runtime, KubeletVersion: d.Kubelet,
+				OperatingSystem: "linux", Architecture: "amd64",
+			},
+		},
+	}
+	for _, t := range d.Taints {
+		kv, effect, _ := strings.Cut(t, ":")
+		k, v, _ := strings.Cut(kv, "=")
+		n.Spec.Taints = append(n.Spec.Taints, corev1.Taint{Key: k, Value: v, Effect: corev1.TaintEffect(effect)})
+	}
+	for _, a := range d.Addresses {
+		typ, addr, _ := strings.Cut(a, " ")
+		n.Status.Addresses = append(n.Status.Addresses, corev1.NodeAddress{Type: corev1.NodeAddressType(typ), Address: addr})
+	}
+	for _, c := range d.Conditions {
+		n.Status.Conditions = append(n.Status.Conditions, corev1.NodeCondition{
+			Type: corev1.NodeConditionType(c.Type), Status: corev1.ConditionStatus(c.Status), Reason: c.Reason, Message: c.Message,
+			LastTransitionTime: metav1.NewTime(c.Since), LastHeartbeatTime: metav1.Now(),
+		})
+	}
+	return n
+}
+
+func resourceList(cpuMil int, memBytes int64) corev1.ResourceList {
+	l := corev1.ResourceList{}
+	if cpuMil > 0 {
+		l[corev1.ResourceCPU] = *resource.NewMilliQuantity(int64(cpuMil), resource.DecimalSI)
+	}
+	if memBytes > 0 {
+		l[corev1.ResourceMemory] = *resource.NewQuantity(memBytes, resource.BinarySI)
+	}
+	return l
+}
+
+func nodeResources(r domain.Resources) corev1.ResourceList {
+	l := resourceList(r.CPUm, r.MemBytes)
+	l[corev1.ResourcePods] = *resource.NewQuantity(int64(r.Pods), resource.DecimalSI)
+	l[corev1.ResourceEphemeralStorage] = *resource.NewQuantity(r.DiskBytes, resource.BinarySI)
+	return l
+}
+
+// mockProbe turns "http-get :8080/healthz every 10s" back into a probe.
+func mockProbe(s string) *corev1.Probe {
+	var port int
+	var path string
+	var period int32
+	if _, err := fmt.Sscanf(strings.Replace(s, "/", " /", 1), "http-get :%d %s every %ds", &port, &path, &period); err != nil {
+		return nil
+	}
+	return &corev1.Probe{
+		ProbeHandler:  corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: path, Port: intstr.FromInt32(int32(port))}},
+		PeriodSeconds: period,
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -7,17 +7,21 @@ import (
 	"strings"
 	"time"
 
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+
 	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+	"github.com/HaPhanBaoMinh/kmet/internal/logs"
 )
 
+// Repo serves made-up data. The app calls it from concurrent commands, so
+// it only uses the goroutine-safe top-level math/rand functions.
 type Repo struct {
 	start time.Time
-	rnd   *rand.Rand
 }
 
 func New() *Repo {
-	src := rand.NewSource(time.Now().UnixNano())
-	return &Repo{start: time.Now(), rnd: rand.New(src)}
+	return &Repo{start: time.Now()}
 }
 
 func (r *Repo) ListNodes(ctx context.Context) ([]domain.NodeMetric, error) {
@@ -26,119 +30,259 @@ func (r *Repo) ListNodes(ctx context.Context) ([]domain.NodeMetric, error) {
 	for i, n := range base {
 		c := clamp01(0.45 + 0.25*(r.noise(i)))
 		m := clamp01(0.42 + 0.28*(r.noise(i+10)))
+		pods, _ := r.ListPods(ctx, "all", domain.Selector{Fields: "spec.nodeName=" + n})
+		var cpuReq int
+		var memReq int64
+		for _, p := range pods {
+			cpuReq, memReq = cpuReq+p.CPUReqm, memReq+p.MemReqBytes
+		}
 		out = append(out, domain.NodeMetric{
 			NodeName: n,
 			CPUUsed:  c,
 			MEMUsed:  m,
-			Pods:     70 + i*5 + int(10*r.rnd.Float64()),
+			Pods:     70 + i*5 + int(10*rand.Float64()),
 			K8sVer:   "1.29",
-			CPUTrend: trendFrom(c, 60, r.rnd),
-			MEMTrend: trendFrom(m, 60, r.rnd),
+			CPUTrend: trendFrom(c, 1, 60),
+			MEMTrend: trendFrom(m, 1, 60),
+			Pressure: mockPressure[n],
+			Labels:   mockNodeLabels(n),
+
+			CPUAllocm:     3920,
+			MemAllocBytes: 15 << 30,
+			CPUReqm:       cpuReq,
+			MemReqBytes:   memReq,
 		})
 	}
 	return out, nil
 }
 
-func matchesSelector(podName, container, selector string) bool {
-	sel := strings.TrimSpace(selector)
-	if sel == "" {
-		return true
-	}
-	parts := strings.Split(sel, ",")
-	for _, part := range parts {
-		t := strings.TrimSpace(part)
-		if t == "" {
-			continue
-		}
-		if strings.Contains(t, "=") {
-			kv := strings.SplitN(t, "=", 2)
-			key := strings.TrimSpace(kv[0])
-			val := strings.TrimSpace(kv[1])
-			switch key {
-			case "app", "component", "name":
-				if !(strings.EqualFold(container, val) || strings.Contains(podName, val)) {
-					return false
-				}
-			default:
-				if !(strings.Contains(podName, val) || strings.Contains(container, val)) {
-					return false
-				}
-			}
-		} else {
-			if !(strings.Contains(podName, t) || strings.Contains(container, t)) {
-				return false
-			}
-		}
+// mockPressure gives one node a pressure condition, so the tint shows.
+var mockPressure = map[string][]string{"ip-10-0-2-7": {"MemoryPressure"}}
+
+var mockPods = []struct {
+	name, ctn, node, owner string
+	labels                 labels.Set
+	restarts               int
+	cpuLimm                int   // 0 = no limit
+	memLimBytes            int64 // 0 = no limit
+	ready                  string
+}{
+	{"api-7cfb9d9c9c-9tghd", "api", "ip-10-0-1-5", "api", labels.Set{"app": "api", "tier": "frontend", "pod-template-hash": "7cfb9d9c9c"}, 0, 500, 1 << 30, "1/1"},
+	{"api-7cfb9d9c9c-sj2lq", "api", "ip-10-0-1-12", "api", labels.Set{"app": "api", "tier": "frontend", "pod-template-hash": "7cfb9d9c9c"}, 0, 500, 1 << 30, "1/1"},
+	{"worker-5f7dcbffd6-2jqkz", "worker", "ip-10-0-2-3", "worker", labels.Set{"app": "worker", "tier": "backend", "pod-template-hash": "5f7dcbffd6"}, 3, 0, 0, "0/1"},
+	{"cart-6d79f8b5f7-m2x8l", "cart", "ip-10-0-2-7", "cart", labels.Set{"app": "cart", "tier": "backend", "pod-template-hash": "6d79f8b5f7"}, 0, 0, 1 << 30, "1/1"},
+}
+
+// podFields are the pod fields the API server lets you select on.
+func podFields(name, ns, node, phase string) fields.Set {
+	return fields.Set{
+		"metadata.name":      name,
+		"metadata.namespace": ns,
+		"spec.nodeName":      node,
+		"status.phase":       phase,
 	}
-	return true
 }
 
-func (r *Repo) ListPods(ctx context.Context, ns string, selector string) ([]domain.PodMetric, error) {
-	pods := []struct {
-		name, ctn, node string
-	}{{"api-7cfb9d9c9c-9tghd", "api", "ip-10-0-1-5"},
-		{"api-7cfb9d9c9c-sj2lq", "api", "ip-10-0-1-12"},
-		{"worker-5f7dcbffd6-2jqkz", "worker", "ip-10-0-2-3"},
-		{"cart-6d79f8b5f7-m2x8l", "cart", "ip-10-0-2-7"},
+func (r *Repo) ListPods(ctx context.Context, ns string, sel domain.Selector) ([]domain.PodMetric, error) {
+	ls, err := labels.Parse(sel.Labels)
+	if err != nil {
+		return nil, err
+	}
+	fs, err := fields.ParseSelector(sel.Fields)
+	if err != nil {
+		return nil, err
+	}
+	if ns == "" || ns == "all" {
+		ns = "default" // every mock pod lives there
 	}
 	var out []domain.PodMetric
-	for i, p := range pods {
-		// selector mock
-		if !matchesSelector(p.name, p.ctn, selector) {
+	for i, p := range mockPods {
+		if !ls.Matches(p.labels) || !fs.Matches(podFields(p.name, ns, p.node, "Running")) {
 			continue
 		}
-		cpu := int(80 + 60*r.rnd.Float64()) // m
-		mem := int64(500*1024*1024 + int64(300*1024*1024*r.rnd.Float64()))
+		cpu := int(80 + 60*rand.Float64()) // m
+		mem := int64(500*1024*1024 + int64(300*1024*1024*rand.Float64()))
 		out = append(out, domain.PodMetric{
-			Namespace:   coalesce(ns, "default"),
-			PodName:     p.name,
-			Container:   p.ctn,
-			NodeName:    p.node,
-			CPUm:        cpu,
-			MemBytes:    mem,
-			CPUReqm:     100,
-			MemReqBytes: 256 * 1024 * 1024,
-			Ready:       "1/1",
-			Phase:       "Running",
-			CPUTrend:    trendFrom(float64(cpu)/500.0, 60, r.rnd),                // normalize ~0..1
-			MemTrend:    trendFrom(float64(mem)/(1.2*1024*1024*1024), 60, r.rnd), // ~0..1
+			Namespace:    ns,
+			PodName:      p.name,
+			Container:    p.ctn,
+			NodeName:     p.node,
+			CPUm:         cpu,
+			MemBytes:     mem,
+			CPUReqm:      150,
+			MemReqBytes:  768 * 1024 * 1024,
+			CPULimm:      p.cpuLimm,
+			MemLimBytes:  p.memLimBytes,
+			QoS:          "Burstable",
+			Labels:       p.labels,
+			NodeCPUm:     4000,
+			NodeMemBytes: 16 << 30,
+			NodePressure: mockPressure[p.node],
+			Ready:        p.ready,
+			Phase:        "Running",
+			Restarts:     p.restarts,
+			Created:      r.start.Add(-time.Duration(i+1) * 7 * time.Hour),
+			OwnerKind:    "Deployment",
+			OwnerName:    p.owner,
+			CPUTrend:     trendFrom(float64(cpu)/500.0, 500, 60),                               // normalize ~0..1
+			MemTrend:     trendFrom(float64(mem)/(1.2*1024*1024*1024), 1.2*1024*1024*1024, 60), // ~0..1
 		})
-		if i == 0 {
-			out[i].CPUm = 120
-			out[i].MemBytes = 612 * 1024 * 1024
+		if p.name == mockPods[0].name {
+			out[len(out)-1].CPUm = 120
+			out[len(out)-1].MemBytes = 612 * 1024 * 1024
 		}
 	}
 	return out, nil
 }
 
+// DescribePod makes up the detail of a mock pod; the worker is crash
+// looping so every section has something to show.
+func (r *Repo) DescribePod(ctx context.Context, ns, name string) (domain.PodDetail, error) {
+	for i, p := range mockPods {
+		if p.name != name {
+			continue
+		}
+		started := r.start.Add(-time.Duration(i+1) * 7 * time.Hour)
+		ctr := domain.ContainerDetail{
+			Name: p.ctn, Image: fmt.Sprintf("ghcr.io/acme/%s:1.%d.0", p.ctn, 4+i),
+			CPUReqm: 150, CPULimm: p.cpuLimm, MemReqBytes: 768 << 20, MemLimBytes: p.memLimBytes,
+			Ready: p.ready == "1/1", State: "Running", Restarts: p.restarts,
+			Liveness:  "http-get :8080/healthz every 10s",
+			Readiness: "http-get :8080/ready every 5s",
+		}
+		events := []domain.Event{
+			{Type: "Normal", Reason: "Scheduled", Message: "Successfully assigned " + ns + "/" + name + " to " + p.node, Count: 1, Last: started},
+			{Type: "Normal", Reason: "Pulled", Message: "Container image \"" + ctr.Image + "\" already present on machine", Count: 1, Last: started.Add(2 * time.Second)},
+			{Type: "Normal", Reason: "Started", Message: "Started container " + p.ctn, Count: 1, Last: started.Add(3 * time.Second)},
+		}
+		ready := "True"
+		if !ctr.Ready {
+			ready = "False"
+			ctr.State, ctr.LastExit = "Waiting: CrashLoopBackOff", "OOMKilled (137)"
+			events = append(events,
+				domain.Event{Type: "Warning", Reason: "BackOff", Message: "Back-off restarting failed container " + p.ctn, Count: p.restarts * 4, Last: time.Now().Add(-40 * time.Second)},
+				domain.Event{Type: "Warning", Reason: "Unhealthy", Message: "Readiness probe failed: connection refused", Count: p.restarts, Last: time.Now().Add(-20 * time.Second)})
+		}
+		return domain.PodDetail{
+			IP: fmt.Sprintf("10.244.%d.%d", i+1, 17+i*9), ServiceAccount: "default", QoS: "Burstable", Started: started,
+			Containers: []domain.ContainerDetail{
+				{Name: "migrate", Image: ctr.Image, Init: true, State: "Terminated: Completed"},
+				ctr,
+			},
+			Conditions: []domain.Condition{
+				{Type: "Initialized", Status: "True", Since: started},
+				{Type: "Ready", Status: ready, Since: started.Add(5 * time.Second)},
+				{Type: "ContainersReady", Status: ready, Since: started.Add(5 * time.Second)},
+				{Type: "PodScheduled", Status: "True", Since: started},
+			},
+			Events: events,
+		}, nil
+	}
+	return domain.PodDetail{}, fmt.Errorf("pods %q not found", name)
+}
+
+// mockNodeLabels spreads the nodes over three zones by their subnet and
+// puts the tainted one in a node group of its own.
+func mockNodeLabels(name string) map[string]string {
+	group := "general"
+	if len(mockTaints[name]) > 0 {
+		group = "batch"
+	}
+	return map[string]string{
+		"kubernetes.io/hostname":           name,
+		"kubernetes.io/os":                 "linux",
+		"node.kubernetes.io/instance-type": "m6i.xlarge",
+		"topology.kubernetes.io/zone":      "eu-west-1" + map[byte]string{'1': "a", '2': "b", '3': "c"}[name[8]],
+		"eks.amazonaws.com/nodegroup":      group,
+	}
+}
+
+// mockTaints keeps batch work on one node.
+var mockTaints = map[string][]string{"ip-10-0-3-2": {"dedicated=batch:PreferNoSchedule"}}
+
+// DescribeNode makes up the detail of a mock node.
+func (r *Repo) DescribeNode(ctx context.Context, name string) (domain.NodeDetail, error) {
+	mem, reason := "False", "KubeletHasSufficientMemory"
+	var events []domain.Event
+	if len(mockPressure[name]) > 0 {
+		mem, reason = "True", "KubeletHasInsufficientMemory"
+		events = append(events, domain.Event{Type: "Warning", Reason: "EvictionThresholdMet", Message: "Attempting to reclaim memory", Count: 3, Last: time.Now().Add(-2 * time.Minute)})
+	}
+	return domain.NodeDetail{
+		Capacity:    domain.Resources{CPUm: 4000, MemBytes: 16 << 30, Pods: 110, DiskBytes: 100 << 30},
+		Allocatable: domain.Resources{CPUm: 3920, MemBytes: 15 << 30, Pods: 110, DiskBytes: 92 << 30},
+		Conditions: []domain.Condition{
+			{Type: "MemoryPressure", Status: mem, Reason: reason, Since: r.start},
+			{Type: "DiskPressure", Status: "False", Reason: "KubeletHasNoDiskPressure", Since: r.start},
+			{Type: "PIDPressure", Status: "False", Reason: "KubeletHasSufficientPID", Since: r.start},
+			{Type: "Ready", Status: "True", Reason: "KubeletReady", Message: "kubelet is posting ready status", Since: r.start},
+		},
+		Taints:    mockTaints[name],
+		Labels:    mockNodeLabels(name),
+		Addresses: []string{"InternalIP " + strings.ReplaceAll(strings.TrimPrefix(name, "ip-"), "-", "."), "Hostname " + name},
+		OS:        "Amazon Linux 2023", Kernel: "6.1.109", Runtime: "containerd://1.7.22", Kubelet: "v1.29.8",
+		Created: r.start.Add(-30 * 24 * time.Hour),
+		Events:  events,
+	}, nil
+}
+
+// ListWarnings makes up what DescribePod and DescribeNode warn about, for
+// every object at once.
+func (r *Repo) ListWarnings(ctx context.Context, ns string) ([]domain.Event, error) {
+	if ns == "" || ns == "all" {
+		ns = "default"
+	}
+	var out []domain.Event
+	for _, p := range mockPods {
+		if p.ready == "1/1" {
+			continue
+		}
+		obj := "Pod/" + p.name
+		out = append(out,
+			domain.Event{Type: "Warning", Reason: "Unhealthy", Message: "Readiness probe failed: connection refused", Count: p.restarts, Last: time.Now().Add(-20 * time.Second), Namespace: ns, Object: obj},
+			domain.Event{Type: "Warning", Reason: "BackOff", Message: "Back-off restarting failed container " + p.ctn, Count: p.restarts * 4, Last: time.Now().Add(-40 * time.Second), Namespace: ns, Object: obj})
+	}
+	for n := range mockPressure {
+		out = append(out, domain.Event{Type: "Warning", Reason: "EvictionThresholdMet", Message: "Attempting to reclaim memory", Count: 3, Last: time.Now().Add(-2 * time.Minute), Object: "Node/" + n})
+	}
+	return out, nil
+}
+
 func (r *Repo) StreamLogs(ctx context.Context, t domain.LogsTarget) (<-chan domain.LogLine, error) {
 	ch := make(chan domain.LogLine, 100)
+	srcs := sourcesOf(t)
 	go func() {
 		defer close(ch)
+		send := logs.NewSender(ch)
+
+		// backfill history with the same 500ms cadence, ending "now"
+		now := time.Now()
+		n := backfill(t, now)
+		for i := 1; i <= n; i++ {
+			ln := mockLine(i, now.Add(-time.Duration(n-i)*500*time.Millisecond), srcs[i%len(srcs)])
+			if t.Previous && i == n {
+				ln = domain.LogLine{Time: ln.Time, Text: "panic: runtime error: invalid memory address", Source: ln.Source}
+				logs.Parse(&ln)
+			}
+			if !send.Send(ctx.Done(), ln) {
+				return
+			}
+		}
+		if t.Previous {
+			return // a dead instance doesn't produce new lines
+		}
+
 		tick := time.NewTicker(500 * time.Millisecond)
 		defer tick.Stop()
-		i := 0
+		i := n
 		for {
 			select {
 			case <-ctx.Done():
 				return
 			case ts := <-tick.C:
 				i++
-				level := "INFO"
-				msg := "request ok"
-				if i%13 == 0 {
-					level = "WARN"
-					msg = "queue lag=233ms"
-				}
-				if i%37 == 0 {
-					level = "ERROR"
-					msg = "db timeout op=save_order retry=1"
-				}
-				ch <- domain.LogLine{
-					Time:   ts,
-					Level:  level,
-					Text:   msg,
-					Source: fmt.Sprintf("%s/%s", t.Name, coalesce(t.Container, "api")),
+				if !send.Send(ctx.Done(), mockLine(i, ts, srcs[i%len(srcs)])) {
+					return
 				}
 			}
 		}
@@ -146,16 +290,111 @@ func (r *Repo) StreamLogs(ctx context.Context, t domain.LogsTarget) (<-chan doma
 	return ch, nil
 }
 
+// NodeLogServices mimics a kubelet with the node log query enabled.
+func (r *Repo) NodeLogServices(ctx context.Context, node string) ([]string, error) {
+	return []string{"kubelet", "containerd", "kube-proxy.log"}, nil
+}
+
+// sourcesOf fans workload and selector targets out to the fake pods they
+// would match, so multi-pod tailing shows interleaved sources.
+func sourcesOf(t domain.LogsTarget) []string {
+	if t.Kind == "Node" {
+		return []string{t.Name + "/" + coalesce(t.Service, "events")}
+	}
+	if t.Kind == "Pod" || t.Kind == "" {
+		return []string{fmt.Sprintf("%s/%s", t.Name, coalesce(t.Container, "api"))}
+	}
+	var sel labels.Selector = labels.Nothing()
+	if t.Kind == "Selector" {
+		if ls, err := labels.Parse(t.Name); err == nil {
+			sel = ls
+		}
+	}
+	var out []string
+	for _, p := range mockPods {
+		if sel.Matches(p.labels) || p.owner == t.Name {
+			out = append(out, fmt.Sprintf("%s/%s", p.name, coalesce(t.Container, p.ctn)))
+		}
+	}
+	if len(out) == 0 {
+		out = append(out, fmt.Sprintf("%s/%s", t.Name, coalesce(t.Container, "main")))
+	}
+	return out
+}
+
+// backfill is how many lines of history the target's range asks for.
+func backfill(t domain.LogsTarget, now time.Time) int {
+	n := 200 // "everything" the fake container has logged
+	if t.TailLines > 0 && int(t.TailLines) < n {
+		n = int(t.TailLines)
+	}
+	since := time.Duration(t.SinceSeconds) * time.Second
+	if !t.SinceTime.IsZero() {
+		since = now.Sub(t.SinceTime)
+	}
+	if since > 0 && int(since/(500*time.Millisecond)) < n {
+		n = int(since / (500 * time.Millisecond))
+	}
+	return n
+}
+
+// mockLine cycles through plain, logfmt and JSON lines so every parser in
+// the chain gets exercised.
+func mockLine(i int, ts time.Time, src string) domain.LogLine {
+	if strings.HasSuffix(src, "/kubelet") || strings.HasSuffix(src, "/containerd") {
+		return nodeLine(i, ts, src)
+	}
+	text := "request ok"
+	switch {
+	case i%37 == 0:
+		text = fmt.Sprintf(`{"level":"error","ts":%d.%03d,"msg":"db timeout","op":"save_order","retry":1}`,
+			ts.Unix(), ts.Nanosecond()/1e6)
+	case i%13 == 0:
+		text = `level=warn msg="queue lag" lag=233ms queue=orders`
+	case i%5 == 0:
+		text = fmt.Sprintf(`{"severity":"D","message":"cache stats","hits":%d,"error_count":0}`, 100+i)
+	case i%3 == 0:
+		text = fmt.Sprintf(`level=info msg="GET /api/orders" status=200 dur=%dms error_count=0`, 3+i%17)
+	}
+	ln := domain.LogLine{Time: ts, Text: text, Source: src}
+	logs.Parse(&ln)
+	return ln
+}
+
+// nodeLine is a klog-style line as the kubelet and runtimes write them.
+func nodeLine(i int, ts time.Time, src string) domain.LogLine {
+	sev, msg := "I", `"SyncLoop (PLEG): event for pod" pod="default/api-7cfb9d9c9c-9tghd"`
+	switch {
+	case i%29 == 0:
+		sev, msg = "E", `"Failed to pull image" err="rpc error: code = NotFound" image="cart:v9"`
+	case i%11 == 0:
+		sev, msg = "W", `"Nameserver limits exceeded" omittedNameservers=["10.0.0.3"]`
+	case i%4 == 0:
+		msg = `"Probe succeeded" probeType="Readiness" pod="default/cart-6d79f8b5f7-m2x8l"`
+	}
+	ln := domain.LogLine{
+		Time:   ts,
+		Text:   fmt.Sprintf("%s%s    1 kubelet.go:%d] %s", sev, ts.Format("0102 15:04:05.000000"), 2000+i%400, msg),
+		Source: src,
+	}
+	logs.Parse(&ln)
+	return ln
+}
+
 // helpers
-func trendFrom(base float64, n int, r *rand.Rand) domain.Trend {
+// trendFrom makes n samples, 2s apart up to now, wandering around base;
+// unit is what 1.0 is in the metric's unit.
+func trendFrom(base, unit float64, n int) domain.Trend {
 	v := clamp01(base)
-	s := make([]float64, n)
-	for i := range s {
-		v += (r.Float64() - 0.5) * 0.05
+	t := domain.Trend{Samples: make([]float64, n), Values: make([]float64, n), Times: make([]time.Time, n), Window: time.Minute}
+	now := time.Now()
+	for i := range t.Samples {
+		v += (rand.Float64() - 0.5) * 0.05
 		v = clamp01(v)
-		s[i] = v
+		t.Samples[i], t.Values[i] = v, v*unit
+		t.Times[i] = now.Add(-time.Duration(n-1-i) * 2 * time.Second)
 	}
-	return domain.Trend{Samples: s, Window: time.Minute}
+	return t
 }
 
 func clamp01(f float64) float64 {
@@ -169,7 +408,7 @@ func clamp01(f float64) float64 {
 }
 
 func (r *Repo) noise(seed int) float64 {
-	return (mathSin(float64(time.Since(r.start)/time.Second)) + float64(seed%3)*0.1 + r.rnd.Float64()*0.2)
+	return (mathSin(float64(time.Since(r.start)/time.Second)) + float64(seed%3)*0.1 + rand.Float64()*0.2)
 }
 
 func coalesce(s, def string) string {
@@ -0,0 +1,82 @@
+package logs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+)
+
+// FormatText is the plain-text layout used for exports and tee files.
+func FormatText(ln domain.LogLine) string {
+	return fmt.Sprintf("%s %-5s [%s] %s", ln.Time.Format(time.RFC3339Nano), ln.Level, ln.Source, ln.Text)
+}
+
+func WriteText(w io.Writer, lines []domain.LogLine) error {
+	bw := bufio.NewWriter(w)
+	for _, ln := range lines {
+		bw.WriteString(FormatText(ln))
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+type record struct {
+	Time   time.Time         `json:"time"`
+	Level  string            `json:"level"`
+	Source string            `json:"source"`
+	Text   string            `json:"text"`
+	Msg    string            `json:"msg,omitempty"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// WriteNDJSON writes one JSON object per line.
+func WriteNDJSON(w io.Writer, lines []domain.LogLine) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for _, ln := range lines {
+		rec := record{Time: ln.Time, Level: ln.Level, Source: ln.Source, Text: ln.Text, Msg: ln.Msg}
+		if len(ln.Fields) > 0 {
+			rec.Fields = make(map[string]string, len(ln.Fields))
+			for _, f := range ln.Fields {
+				rec.Fields[f.Key] = f.Value
+			}
+		}
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// IsNDJSON reports whether path asks for NDJSON rather than text.
+func IsNDJSON(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ndjson", ".jsonl", ".json":
+		return true
+	}
+	return false
+}
+
+// Export writes lines to path, picking the format from the extension.
+func Export(path string, lines []domain.LogLine) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	write := WriteText
+	if IsNDJSON(path) {
+		write = WriteNDJSON
+	}
+	if err := write(f, lines); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,309 @@
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+)
+
+// Parser extracts structure from a raw line. Parse returns false when the
+// line isn't in the parser's format so the next parser in a Chain can try.
+type Parser interface {
+	Parse(ln *domain.LogLine) bool
+}
+
+// Chain tries each parser in order; plain lines get a heuristic level. The
+// time the line came with is kept in Received.
+type Chain []Parser
+
+func (c Chain) Parse(ln *domain.LogLine) {
+	if ln.Received.IsZero() {
+		ln.Received = ln.Time
+	}
+	for _, p := range c {
+		if p.Parse(ln) {
+			if ln.Level == "" {
+				ln.Level = LevelFromText(ln.Msg)
+			}
+			return
+		}
+	}
+	ln.Level = LevelFromText(ln.Text)
+}
+
+// Default is the chain used by the repos. Replace or extend it to plug in
+// other formats.
+var Default = Chain{JSON{}, Klog{}, Logfmt{}}
+
+// WithFields is the default chain keeping only the given fields of JSON and
+// logfmt lines, in that order; none keeps them all.
+func WithFields(keys []string) Chain {
+	return Chain{JSON{Keys: keys}, Klog{}, Logfmt{Keys: keys}}
+}
+
+// Parse runs the Default chain on ln.
+func Parse(ln *domain.LogLine) { Default.Parse(ln) }
+
+var (
+	levelKeys = []string{"level", "lvl", "severity", "log.level", "levelname", "loglevel"}
+	msgKeys   = []string{"msg", "message", "log", "text"}
+	timeKeys  = []string{"time", "ts", "timestamp", "@timestamp", "t"}
+)
+
+// JSON handles one-object-per-line loggers (zap, logrus, pino, bunyan...).
+// Keys selects which remaining fields are kept; empty keeps them all.
+type JSON struct {
+	Keys []string
+}
+
+func (p JSON) Parse(ln *domain.LogLine) bool {
+	s := strings.TrimSpace(ln.Text)
+	if !strings.HasPrefix(s, "{") || !strings.HasSuffix(s, "}") {
+		return false
+	}
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(s), &obj); err != nil {
+		return false
+	}
+	kv := make(map[string]string, len(obj))
+	for k, v := range obj {
+		kv[k] = jsonString(v)
+	}
+	apply(ln, "json", kv, p.Keys)
+	return true
+}
+
+func jsonString(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case nil:
+		return "null"
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		b, _ := json.Marshal(t)
+		return string(b)
+	}
+}
+
+// Logfmt handles key=value lines. A line only counts as logfmt when most of
+// its tokens are pairs, so prose with a stray "a=b" stays raw.
+type Logfmt struct {
+	Keys []string
+}
+
+func (p Logfmt) Parse(ln *domain.LogLine) bool {
+	pairs, loose := splitLogfmt(ln.Text)
+	if len(pairs) < 2 || loose > len(pairs)/2 {
+		return false
+	}
+	kv := make(map[string]string, len(pairs))
+	for _, f := range pairs {
+		kv[f.Key] = f.Value
+	}
+	apply(ln, "logfmt", kv, p.Keys)
+	return true
+}
+
+// splitLogfmt tokenizes key=value / key="quoted value" pairs and counts the
+// tokens that weren't pairs.
+func splitLogfmt(s string) (pairs []domain.Field, loose int) {
+	for i := 0; i < len(s); {
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		if i >= len(s) {
+			break
+		}
+		start := i
+		for i < len(s) && s[i] != ' ' && s[i] != '=' {
+			i++
+		}
+		key := s[start:i]
+		if i >= len(s) || s[i] != '=' || key == "" {
+			for i < len(s) && s[i] != ' ' {
+				i++
+			}
+			loose++
+			continue
+		}
+		i++ // '='
+		var val string
+		if i < len(s) && s[i] == '"' {
+			j := i + 1
+			for j < len(s) && s[j] != '"' {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(s) {
+				j = len(s) - 1
+			}
+			if v, err := strconv.Unquote(s[i : j+1]); err == nil {
+				val = v
+			} else {
+				val = strings.Trim(s[i:j+1], `"`)
+			}
+			i = j + 1
+		} else {
+			vs := i
+			for i < len(s) && s[i] != ' ' {
+				i++
+			}
+			val = s[vs:i]
+		}
+		pairs = append(pairs, domain.Field{Key: key, Value: val})
+	}
+	return pairs, loose
+}
+
+// Klog handles the glog/klog header used by Kubernetes components:
+// "E0102 15:04:05.000000    1234 file.go:42] message". The header has no
+// year or zone, so the kubelet's time wins; without one the header is read
+// as UTC in the year that doesn't put it in the future.
+type Klog struct{}
+
+var klogRe = regexp.MustCompile(`^([IWEF])(\d{2})(\d{2}) (\d{2}:\d{2}:\d{2}\.\d+)\s+\d+ ([^\]]+)\] (.*)$`)
+
+func (Klog) Parse(ln *domain.LogLine) bool {
+	m := klogRe.FindStringSubmatch(ln.Text)
+	if m == nil {
+		return false
+	}
+	ln.Format = "klog"
+	ln.Level = NormalizeLevel(m[1])
+	ln.Msg = m[6]
+	ln.Fields = []domain.Field{{Key: "caller", Value: m[5]}}
+	if ln.Time.IsZero() {
+		now := time.Now().UTC()
+		if ts, err := time.Parse("2006 0102 15:04:05.000000",
+			fmt.Sprintf("%d %s%s %s", now.Year(), m[2], m[3], m[4])); err == nil {
+			if ts.After(now.Add(24 * time.Hour)) {
+				ts = ts.AddDate(-1, 0, 0) // December's lines read in January
+			}
+			ln.Time = ts.Local()
+		}
+	}
+	return true
+}
+
+// apply moves the well-known keys out of kv and keeps the rest as fields;
+// a time the line carries replaces the kubelet's.
+func apply(ln *domain.LogLine, format string, kv map[string]string, keep []string) {
+	ln.Format = format
+	if v, ok := take(kv, levelKeys); ok {
+		ln.Level = NormalizeLevel(v)
+	}
+	if v, ok := take(kv, msgKeys); ok {
+		ln.Msg = v
+	}
+	if v, ok := take(kv, timeKeys); ok {
+		if ts, ok := parseTime(v); ok {
+			ln.Time = ts
+		}
+	}
+	ln.Fields = ln.Fields[:0]
+	if len(keep) > 0 {
+		for _, k := range keep {
+			if v, ok := kv[k]; ok {
+				ln.Fields = append(ln.Fields, domain.Field{Key: k, Value: v})
+			}
+		}
+		return
+	}
+	keys := make([]string, 0, len(kv))
+	for k := range kv {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		ln.Fields = append(ln.Fields, domain.Field{Key: k, Value: kv[k]})
+	}
+}
+
+func take(kv map[string]string, keys []string) (string, bool) {
+	for _, k := range keys {
+		if v, ok := kv[k]; ok {
+			delete(kv, k)
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func parseTime(v string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.000", "2006-01-02 15:04:05"} {
+		if ts, err := time.Parse(layout, v); err == nil {
+			return ts.Local(), true
+		}
+	}
+	// unix seconds (zap) or milliseconds (pino/bunyan)
+	if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 {
+		if f > 1e12 {
+			f /= 1000
+		}
+		sec, frac := math.Modf(f)
+		return time.Unix(int64(sec), int64(frac*1e9)), true
+	}
+	return time.Time{}, false
+}
+
+// NormalizeLevel maps the many spellings loggers use onto
+// DEBUG/INFO/WARN/ERROR/FATAL.
+func NormalizeLevel(v string) string {
+	switch strings.ToUpper(strings.TrimSpace(v)) {
+	case "T", "TRACE", "D", "DEBUG", "DBG", "10", "20":
+		return "DEBUG"
+	case "W", "WARN", "WARNING", "40":
+		return "WARN"
+	case "E", "ERROR", "ERR", "50":
+		return "ERROR"
+	case "F", "FATAL", "PANIC", "DPANIC", "CRIT", "CRITICAL", "ALERT", "EMERG", "EMERGENCY", "60":
+		return "FATAL"
+	default:
+		return "INFO"
+	}
+}
+
+// Rank orders levels so "WARN and above" is a simple comparison.
+func Rank(level string) int {
+	switch NormalizeLevel(level) {
+	case "DEBUG":
+		return 0
+	case "WARN":
+		return 2
+	case "ERROR":
+		return 3
+	case "FATAL":
+		return 4
+	default:
+		return 1
+	}
+}
+
+// Plain lines only count as WARN/ERROR on a standalone level word: shouted
+// ("ERROR"), bracketed ("[error]") or followed by a colon ("error:").
+// "error_count=0" or "no errors" stay INFO.
+var textLevelRe = regexp.MustCompile(`\b(FATAL|PANIC|ERROR|ERR|WARN|WARNING|DEBUG)\b|(?i:\[(fatal|panic|error|err|warn|warning|debug)\])|(?i:\b(fatal|panic|error|warn|warning):)`)
+
+func LevelFromText(s string) string {
+	m := textLevelRe.FindStringSubmatch(s)
+	if m == nil {
+		return "INFO"
+	}
+	for _, g := range m[1:] {
+		if g != "" {
+			return NormalizeLevel(g)
+		}
+	}
+	return "INFO"
+}
@@ -0,0 +1,59 @@
+package logs
+
+import "github.com/HaPhanBaoMinh/kmet/internal/domain"
+
+// Ring is a FIFO of log lines bounded by line count and by the bytes of
+// text it holds; the oldest lines are evicted first. Every pushed line gets
+// a sequence number so callers can tell which of their cached rows are gone.
+type Ring struct {
+	buf      []domain.LogLine
+	head     int // index of the oldest line
+	n        int
+	bytes    int
+	maxBytes int
+	next     uint64 // sequence number of the next pushed line
+}
+
+func NewRing(maxLines, maxBytes int) *Ring {
+	if maxLines < 1 {
+		maxLines = 1
+	}
+	return &Ring{buf: make([]domain.LogLine, maxLines), maxBytes: maxBytes}
+}
+
+// Push appends ln and returns how many old lines were evicted to make room.
+func (r *Ring) Push(ln domain.LogLine) (evicted int) {
+	size := lineSize(ln)
+	for r.n > 0 && (r.n == len(r.buf) || (r.maxBytes > 0 && r.bytes+size > r.maxBytes)) {
+		r.bytes -= lineSize(r.buf[r.head])
+		r.buf[r.head] = domain.LogLine{}
+		r.head = (r.head + 1) % len(r.buf)
+		r.n--
+		evicted++
+	}
+	r.buf[(r.head+r.n)%len(r.buf)] = ln
+	r.n++
+	r.bytes += size
+	r.next++
+	return evicted
+}
+
+func (r *Ring) Len() int   { return r.n }
+func (r *Ring) Bytes() int { return r.bytes }
+
+// At returns the i-th oldest line.
+func (r *Ring) At(i int) domain.LogLine { return r.buf[(r.head+i)%len(r.buf)] }
+
+// FirstSeq is the sequence number of At(0).
+func (r *Ring) FirstSeq() uint64 { return r.next - uint64(r.n) }
+
+func (r *Ring) Reset() {
+	for i := range r.buf {
+		r.buf[i] = domain.LogLine{}
+	}
+	r.head, r.n, r.bytes = 0, 0, 0
+}
+
+func lineSize(ln domain.LogLine) int {
+	return len(ln.Text) + len(ln.Source) + len(ln.Msg)
+}
@@ -0,0 +1,176 @@
+package logs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+)
+
+const (
+	sampleBucket  = 10 * time.Second // resolution of the error-rate series
+	sampleBuckets = 60               // 10 minutes of history
+	sampleRetry   = 15 * time.Second // wait before re-attaching a stream that ended
+)
+
+// Sampler tails the logs of a set of pods in the background and counts
+// WARN/ERROR lines, so error bursts can be shown next to resource usage
+// without opening a logs pane. At most budget streams run at once.
+type Sampler struct {
+	repo   domain.LogsRepo
+	budget int
+
+	mu    sync.Mutex
+	tails map[string]*sampled // by ns/pod/container
+}
+
+type sampled struct {
+	cancel  context.CancelFunc
+	running bool
+	retry   time.Time // don't re-attach before this
+	resume  time.Time // kubelet time of the newest line read; a re-attach starts there
+	head    int64     // bucket number of the newest bucket
+	buckets [sampleBuckets]int
+}
+
+func NewSampler(repo domain.LogsRepo, budget int) *Sampler {
+	return &Sampler{repo: repo, budget: budget, tails: map[string]*sampled{}}
+}
+
+func sampleKey(t domain.LogsTarget) string {
+	return t.Namespace + "/" + t.Name + "/" + t.Container
+}
+
+// Sync samples the first budget targets and stops sampling everything else.
+// Call it whenever the set of pods in view changes; it's cheap when it hasn't.
+func (s *Sampler) Sync(ctx context.Context, targets []domain.LogsTarget) {
+	if len(targets) > s.budget {
+		targets = targets[:s.budget]
+	}
+	want := make(map[string]domain.LogsTarget, len(targets))
+	for _, t := range targets {
+		want[sampleKey(t)] = t
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, st := range s.tails {
+		if _, ok := want[key]; !ok {
+			if st.cancel != nil {
+				st.cancel()
+			}
+			delete(s.tails, key)
+		}
+	}
+	now := time.Now()
+	for key, t := range want {
+		st := s.tails[key]
+		if st == nil {
+			st = &sampled{}
+			s.tails[key] = st
+		}
+		if st.running || now.Before(st.retry) {
+			continue
+		}
+		tctx, cancel := context.WithCancel(ctx)
+		st.cancel, st.running = cancel, true
+		// the last minute of history gives a rate right away; a re-attach
+		// picks up at the newest line read. SinceTime is whole seconds, so
+		// lines up to that one come again and are skipped.
+		t.SinceSeconds, t.TailLines = 60, 0
+		if !st.resume.IsZero() {
+			t.SinceTime = st.resume
+		}
+		go s.tail(tctx, st, t, st.resume)
+	}
+}
+
+// tail counts the WARN+ lines of one stream newer than after. Lines are
+// bucketed on the kubelet's time: the time a line carries may be in any
+// zone (a klog header has none).
+func (s *Sampler) tail(ctx context.Context, st *sampled, t domain.LogsTarget, after time.Time) {
+	defer func() {
+		s.mu.Lock()
+		st.running = false
+		st.retry = time.Now().Add(sampleRetry)
+		s.mu.Unlock()
+	}()
+	ch, err := s.repo.StreamLogs(ctx, t)
+	if err != nil {
+		return
+	}
+	for ln := range ch {
+		at := ln.Received
+		if at.IsZero() {
+			at = ln.Time
+		}
+		if !at.After(after) {
+			continue
+		}
+		s.mu.Lock()
+		if at.After(st.resume) {
+			st.resume = at
+		}
+		if Rank(ln.Level) >= Rank("WARN") {
+			st.add(at)
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (st *sampled) add(ts time.Time) {
+	b := ts.UnixNano() / int64(sampleBucket)
+	switch {
+	case b > st.head:
+		for i := st.head + 1; i <= b && i <= st.head+sampleBuckets; i++ {
+			st.buckets[i%sampleBuckets] = 0
+		}
+		st.head = b
+	case b <= st.head-sampleBuckets:
+		return // older than the history we keep
+	}
+	st.buckets[b%sampleBuckets]++
+}
+
+// count is the number of lines in bucket b, 0 if it's outside the history.
+func (st *sampled) count(b int64) int {
+	if b > st.head || b <= st.head-sampleBuckets {
+		return 0
+	}
+	return st.buckets[b%sampleBuckets]
+}
+
+// Rate returns WARN/ERROR lines in the trailing minute and a per-minute
+// series over the last 10 minutes, oldest first. ok is false for pods that
+// aren't being sampled.
+func (s *Sampler) Rate(t domain.LogsTarget) (perMin int, series []float64, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.tails[sampleKey(t)]
+	if st == nil {
+		return 0, nil, false
+	}
+	now := time.Now().UnixNano() / int64(sampleBucket)
+	perBucket := int(time.Minute / sampleBucket)
+	for b := now - int64(perBucket) + 1; b <= now; b++ {
+		perMin += st.count(b)
+	}
+	series = make([]float64, sampleBuckets)
+	for i := range series {
+		series[i] = float64(st.count(now-sampleBuckets+1+int64(i)) * perBucket)
+	}
+	return perMin, series, true
+}
+
+// Close stops every stream.
+func (s *Sampler) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, st := range s.tails {
+		if st.cancel != nil {
+			st.cancel()
+		}
+		delete(s.tails, key)
+	}
+}
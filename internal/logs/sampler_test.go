@@ -0,0 +1,107 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+)
+
+// fakeLogs serves one canned stream per StreamLogs call.
+type fakeLogs struct {
+	mu      sync.Mutex
+	streams [][]domain.LogLine
+	targets []domain.LogsTarget
+}
+
+func (f *fakeLogs) StreamLogs(_ context.Context, t domain.LogsTarget) (<-chan domain.LogLine, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.targets = append(f.targets, t)
+	ch := make(chan domain.LogLine, 16)
+	if len(f.streams) > 0 {
+		for _, ln := range f.streams[0] {
+			ch <- ln
+		}
+		f.streams = f.streams[1:]
+	}
+	close(ch)
+	return ch, nil
+}
+
+func received(at time.Time, text string) domain.LogLine {
+	ln := domain.LogLine{Time: at, Text: text}
+	Parse(&ln)
+	return ln
+}
+
+// drain waits for the sampler's stream of t to end and lets it re-attach.
+func drain(t *testing.T, s *Sampler, target domain.LogsTarget) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); ; {
+		s.mu.Lock()
+		st := s.tails[sampleKey(target)]
+		done := !st.running
+		if done {
+			st.retry = time.Time{}
+		}
+		s.mu.Unlock()
+		if done {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("stream didn't end")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSamplerRate(t *testing.T) {
+	now := time.Now()
+	at := func(d time.Duration) time.Time { return now.Add(d).Truncate(time.Microsecond) }
+	// the lines' own clocks are hours off the kubelet's: a klog header read
+	// in another zone, a JSON time in the future
+	klog := func(d time.Duration, sev string) string {
+		return fmt.Sprintf("%s%s    1 main.go:1] boom", sev, at(d).Add(5*time.Hour).Format("0102 15:04:05.000000"))
+	}
+	first := []domain.LogLine{
+		received(at(-30*time.Second), klog(-30*time.Second, "E")),
+		received(at(-20*time.Second), fmt.Sprintf(`{"level":"error","time":%q,"msg":"boom"}`, at(3*time.Hour).Format(time.RFC3339Nano))),
+		received(at(-10*time.Second), klog(-10*time.Second, "W")),
+		received(at(-5*time.Second), klog(-5*time.Second, "I")),
+	}
+	// a re-attach from a whole second replays the lines before it
+	second := append(first[2:4:4], received(at(-2*time.Second), klog(-2*time.Second, "E")))
+	repo := &fakeLogs{streams: [][]domain.LogLine{first, second}}
+	target := domain.LogsTarget{Namespace: "kube-system", Name: "apiserver", Container: "kube-apiserver"}
+
+	s := NewSampler(repo, 1)
+	defer s.Close()
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		perMin int
+		since  time.Time
+	}{
+		{"first attach", 3, time.Time{}},
+		{"re-attach", 4, at(-5 * time.Second)},
+	}
+	for i, tt := range tests {
+		s.Sync(ctx, []domain.LogsTarget{target})
+		drain(t, s, target)
+		perMin, series, ok := s.Rate(target)
+		if !ok || perMin != tt.perMin {
+			t.Errorf("%s: Rate = %d, %v; want %d", tt.name, perMin, ok, tt.perMin)
+		}
+		if len(series) != sampleBuckets {
+			t.Errorf("%s: %d buckets, want %d", tt.name, len(series), sampleBuckets)
+		}
+		if got := repo.targets[i].SinceTime; !got.Equal(tt.since) {
+			t.Errorf("%s: SinceTime = %v, want %v", tt.name, got, tt.since)
+		}
+	}
+}
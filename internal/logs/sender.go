@@ -0,0 +1,45 @@
+package logs
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+)
+
+// sendGrace is how long a producer waits on a full channel before dropping.
+// Long enough to ride out a UI frame, short enough not to stall the stream.
+const sendGrace = 100 * time.Millisecond
+
+// Sender writes to a log channel without ever blocking a stream goroutine for
+// long: when the reader falls behind, lines are dropped and the count is
+// attached to the next line that gets through (LogLine.Dropped). Safe for
+// concurrent use by several tails sharing one channel.
+type Sender struct {
+	out     chan<- domain.LogLine
+	dropped atomic.Int64
+}
+
+func NewSender(out chan<- domain.LogLine) *Sender { return &Sender{out: out} }
+
+// Send reports false only when done is closed. Drops already recorded on ln
+// (by an upstream Sender) are carried over.
+func (s *Sender) Send(done <-chan struct{}, ln domain.LogLine) bool {
+	ln.Dropped += int(s.dropped.Swap(0))
+	select {
+	case s.out <- ln:
+		return true
+	default:
+	}
+	t := time.NewTimer(sendGrace)
+	defer t.Stop()
+	select {
+	case s.out <- ln:
+		return true
+	case <-done:
+		return false
+	case <-t.C:
+		s.dropped.Add(int64(ln.Dropped) + 1)
+		return true
+	}
+}
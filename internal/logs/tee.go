@@ -0,0 +1,160 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/HaPhanBaoMinh/kmet/internal/domain"
+)
+
+const (
+	teeMaxBytes = 10 << 20 // rotate a source file past this size
+	teeKeep     = 3        // rotated generations kept: name.log.1 .. name.log.3
+)
+
+// Tee wraps a LogsRepo and copies every line of every stream it opens to
+// per-source files under dir, rotating them by size. The UI side still sees
+// the stream as usual; lines the UI drops are on disk anyway.
+type Tee struct {
+	repo domain.LogsRepo
+	dir  string
+
+	mu    sync.Mutex
+	files map[string]*teeFile // by source
+}
+
+func NewTee(repo domain.LogsRepo, dir string) (*Tee, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("log tee: %w", err)
+	}
+	return &Tee{repo: repo, dir: dir, files: map[string]*teeFile{}}, nil
+}
+
+func (t *Tee) StreamLogs(ctx context.Context, target domain.LogsTarget) (<-chan domain.LogLine, error) {
+	in, err := t.repo.StreamLogs(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan domain.LogLine, cap(in))
+	go func() {
+		defer close(out)
+		send := NewSender(out)
+		used := map[string]bool{}
+		defer func() {
+			for src := range used {
+				t.release(src)
+			}
+		}()
+		for ln := range in {
+			if !used[ln.Source] {
+				used[ln.Source] = true
+				t.acquire(ln.Source)
+			}
+			t.write(ln)
+			// after cancellation keep draining so the file still gets what
+			// the producer delivers before it notices
+			send.Send(ctx.Done(), ln)
+		}
+	}()
+	return out, nil
+}
+
+// NodeLogServices passes through to the wrapped repo so the node log
+// picker keeps working with -log-tee.
+func (t *Tee) NodeLogServices(ctx context.Context, node string) ([]string, error) {
+	if src, ok := t.repo.(domain.NodeLogSources); ok {
+		return src.NodeLogServices(ctx, node)
+	}
+	return nil, fmt.Errorf("node logs are not supported by this source")
+}
+
+// teeFile is one source's file, shared by all streams that carry it.
+type teeFile struct {
+	path string
+	f    *os.File
+	size int64
+	refs int
+}
+
+func (t *Tee) acquire(src string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tf := t.files[src]
+	if tf == nil {
+		tf = &teeFile{path: filepath.Join(t.dir, SafeName(src)+".log")}
+		t.files[src] = tf
+	}
+	tf.refs++
+}
+
+func (t *Tee) release(src string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tf := t.files[src]
+	if tf == nil {
+		return
+	}
+	if tf.refs--; tf.refs <= 0 {
+		if tf.f != nil {
+			tf.f.Close()
+		}
+		delete(t.files, src)
+	}
+}
+
+func (t *Tee) write(ln domain.LogLine) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tf := t.files[ln.Source]
+	if tf == nil {
+		return
+	}
+	if tf.f == nil || tf.size >= teeMaxBytes {
+		if err := tf.open(tf.f != nil); err != nil {
+			return // best effort: a full disk must not take the UI down
+		}
+	}
+	n, _ := tf.f.WriteString(FormatText(ln) + "\n")
+	tf.size += int64(n)
+}
+
+// open (re)opens the file for appending, shifting older generations first
+// when rotate is set.
+func (tf *teeFile) open(rotate bool) error {
+	if tf.f != nil {
+		tf.f.Close()
+		tf.f = nil
+	}
+	if rotate {
+		for i := teeKeep - 1; i >= 1; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", tf.path, i), fmt.Sprintf("%s.%d", tf.path, i+1))
+		}
+		os.Rename(tf.path, tf.path+".1")
+	}
+	f, err := os.OpenFile(tf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	tf.f, tf.size = f, st.Size()
+	return nil
+}
+
+// SafeName turns "pod/container" into something safe for any filesystem.
+func SafeName(src string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		}
+		return '_'
+	}, src)
+}
@@ -1,16 +1,208 @@
 package styles
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
 
 var (
-	Title     = lipgloss.NewStyle().Bold(true)
-	TabActive = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7DCE13"))
-	Tab       = lipgloss.NewStyle().Foreground(lipgloss.Color("#999999"))
-	Header    = lipgloss.NewStyle().Foreground(lipgloss.Color("#AAAAAA"))
-	Footer    = lipgloss.NewStyle().Foreground(lipgloss.Color("#777777"))
-	Box       = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
-	Danger    = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87"))
-	Warn      = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFAF00"))
-	Good      = lipgloss.NewStyle().Foreground(lipgloss.Color("#5FD7AF"))
-	Faint     = lipgloss.NewStyle().Foreground(lipgloss.Color("#6C6C6C"))
+	Title     lipgloss.Style
+	TabActive lipgloss.Style
+	Tab       lipgloss.Style
+	Header    lipgloss.Style
+	Footer    lipgloss.Style
+	Box       lipgloss.Style
+	Popup     lipgloss.Style // Box for overlays (pickers, chooser)
+	Selected  lipgloss.Style // cursor row of tables and lists
+	Danger    lipgloss.Style
+	Warn      lipgloss.Style
+	Good      lipgloss.Style
+	Faint     lipgloss.Style
+	Bar       lipgloss.Style // usage bars
+	Spark     lipgloss.Style // sparklines
+
+	Match        lipgloss.Style
+	MatchCurrent lipgloss.Style
+
+	// sourcePalette is what log sources are hashed into.
+	sourcePalette []string
+
+	current string
 )
+
+// Theme is a named palette the styles above are built from. Colors are
+// "#RRGGBB" or an ANSI number ("9", "214"); lipgloss downsamples them to
+// what the terminal supports. An empty color leaves the terminal's own and
+// falls back to bold, underline, reverse or faint where the color carried
+// meaning, which is all the monochrome theme is.
+type Theme struct {
+	Accent, Muted, Header, Footer string
+	Border, Selected              string
+	Danger, Warn, Good, Faint     string
+	Bar, Spark                    string
+	MatchFG, MatchBG              string // current search match
+
+	// Sources are picked to stay readable next to each other.
+	Sources []string
+}
+
+var themes = map[string]Theme{
+	"dark": {
+		Accent: "#7DCE13", Muted: "#999999", Header: "#AAAAAA", Footer: "#777777",
+		Selected: "212",
+		Danger:   "#FF5F87", Warn: "#FFAF00", Good: "#5FD7AF", Faint: "#6C6C6C",
+		Bar: "#87AFD7", Spark: "#87D7AF",
+		MatchFG: "#000000", MatchBG: "#FFAF00",
+		Sources: []string{
+			"#5FAFFF", "#FF87AF", "#87D75F", "#FFD75F", "#AF87FF", "#5FD7D7",
+			"#FF8700", "#D7AFFF", "#87AFAF", "#FF5FD7", "#AFD700", "#00AFFF",
+		},
+	},
+	"light": {
+		Accent: "#2E7D32", Muted: "#666666", Header: "#444444", Footer: "#666666",
+		Border: "#9E9E9E", Selected: "#AD1457",
+		Danger: "#C62828", Warn: "#B26A00", Good: "#00796B", Faint: "#9E9E9E",
+		Bar: "#1565C0", Spark: "#00796B",
+		MatchFG: "#000000", MatchBG: "#FFD54F",
+		Sources: []string{
+			"#1565C0", "#AD1457", "#2E7D32", "#8D6E00", "#6A1B9A", "#00838F",
+			"#D84315", "#4527A0", "#37474F", "#C2185B", "#558B2F", "#0277BD",
+		},
+	},
+	// solarized only uses the accent and mid tones, so it reads on both the
+	// dark and the light solarized background.
+	"solarized": {
+		Accent: "#859900", Muted: "#839496", Header: "#93A1A1", Footer: "#657B83",
+		Border: "#586E75", Selected: "#268BD2",
+		Danger: "#DC322F", Warn: "#B58900", Good: "#2AA198", Faint: "#586E75",
+		Bar: "#268BD2", Spark: "#2AA198",
+		MatchFG: "#002B36", MatchBG: "#B58900",
+		Sources: []string{
+			"#268BD2", "#D33682", "#859900", "#B58900", "#6C71C4", "#2AA198", "#CB4B16", "#DC322F",
+		},
+	},
+	// high-contrast sticks to the 16 bright ANSI colors and the terminal's
+	// own foreground for text.
+	"high-contrast": {
+		Accent: "11", Selected: "14",
+		Danger: "9", Warn: "11", Good: "10",
+		Bar: "12", Spark: "10",
+		MatchFG: "0", MatchBG: "11",
+		Sources: []string{"9", "10", "11", "12", "13", "14"},
+	},
+	"monochrome": {},
+}
+
+func init() { apply(themes["dark"]) }
+
+// Themes lists the theme names, sorted.
+func Themes() []string {
+	names := make([]string, 0, len(themes))
+	for n := range themes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the named theme.
+func Lookup(name string) (Theme, bool) {
+	t, ok := themes[name]
+	return t, ok
+}
+
+// Define adds a theme, or replaces one of the same name.
+func Define(name string, t Theme) { themes[name] = t }
+
+// Use switches every style to the named theme. Without color (NO_COLOR
+// set, a dumb terminal) every theme renders as monochrome.
+func Use(name string) error {
+	t, ok := themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme %q (have: %s)", name, strings.Join(Themes(), ", "))
+	}
+	if os.Getenv("NO_COLOR") != "" || lipgloss.ColorProfile() == termenv.Ascii {
+		t = themes["monochrome"]
+		// lipgloss drops bold and reverse along with color under NO_COLOR;
+		// a terminal that has them keeps them, the cursor row needs them.
+		if termenv.NewOutput(os.Stdout).ColorProfile() != termenv.Ascii {
+			lipgloss.SetColorProfile(termenv.ANSI)
+		}
+	}
+	current = name
+	apply(t)
+	return nil
+}
+
+// Current is the name of the theme in use.
+func Current() string { return current }
+
+// ValidColor reports whether c is a color a Theme takes.
+func ValidColor(c string) bool {
+	if strings.HasPrefix(c, "#") {
+		if len(c) != 4 && len(c) != 7 {
+			return false
+		}
+		_, err := strconv.ParseUint(c[1:], 16, 32)
+		return err == nil
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// fg is a style in color c, or attr when the theme has no color for it.
+func fg(c string, attr lipgloss.Style) lipgloss.Style {
+	if c == "" {
+		return attr
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(c))
+}
+
+func apply(t Theme) {
+	plain := lipgloss.NewStyle()
+	Title = plain.Bold(true)
+	TabActive = fg(t.Accent, plain).Bold(true)
+	Tab = fg(t.Muted, plain)
+	Header = fg(t.Header, plain)
+	Footer = fg(t.Footer, plain)
+	Box = plain.Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	if t.Border != "" {
+		Box = Box.BorderForeground(lipgloss.Color(t.Border))
+	}
+	Popup = Box
+	if t.Accent != "" {
+		Popup = Box.BorderForeground(lipgloss.Color(t.Accent))
+	}
+	Selected = fg(t.Selected, plain.Reverse(true)).Bold(true)
+	Danger = fg(t.Danger, plain.Bold(true))
+	Warn = fg(t.Warn, plain.Underline(true))
+	Good = fg(t.Good, plain)
+	Faint = fg(t.Faint, plain.Faint(true))
+	Bar = fg(t.Bar, plain)
+	Spark = fg(t.Spark, plain)
+
+	Match = plain.Reverse(true)
+	MatchCurrent = plain.Reverse(true).Bold(true)
+	if t.MatchBG != "" {
+		MatchCurrent = plain.Background(lipgloss.Color(t.MatchBG)).Foreground(lipgloss.Color(t.MatchFG))
+	}
+	sourcePalette = t.Sources
+}
+
+// Source returns a stable color for a log source, so the same pod/container
+// keeps its color across reconnects and sessions.
+func Source(name string) lipgloss.Style {
+	if len(sourcePalette) == 0 {
+		return lipgloss.NewStyle()
+	}
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(sourcePalette[h.Sum32()%uint32(len(sourcePalette))]))
+}
@@ -0,0 +1,138 @@
+package widgets
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Heatmap draws values in 0..1 as a grid, a row per series and a column per
+// time bucket. A cell's shade is its level and Color gives its color, so
+// the map still reads without colors. Rows that don't fit are scrolled to
+// Top.
+type Heatmap struct {
+	Width, Height int // cells, labels and time axis included
+
+	Rows  []HeatRow
+	Times []time.Time // start of each column
+
+	Row, Col int // cursor cell
+	Top      int // first row shown, see Scroll
+
+	Color      func(v float64) lipgloss.Style
+	Heading    lipgloss.Style // labels of Group rows
+	Cursor     lipgloss.Style
+	Axis       lipgloss.Style
+	TimeFormat string // "15:04:05" when empty
+}
+
+// HeatRow is one row; Values is parallel to the map's Times, NaN where a
+// bucket has no sample.
+type HeatRow struct {
+	Label  string
+	Values []float64
+	Group  bool // a heading row: the summary of the rows under it
+}
+
+// shades are the cell levels, lowest first.
+var shades = []string{"░", "▒", "▓", "█"}
+
+// Shade is the cell drawn for v; a blank for no sample.
+func Shade(v float64) string {
+	if math.IsNaN(v) {
+		return " "
+	}
+	return shades[int(math.Min(float64(len(shades)-1), math.Max(0, v*float64(len(shades)))))]
+}
+
+// Visible is how many rows fit.
+func (h Heatmap) Visible() int { return max(1, h.Height-2) }
+
+// Scroll is the Top that keeps the cursor row in view, moving top as little
+// as it can.
+func (h Heatmap) Scroll(top int) int {
+	vis := h.Visible()
+	switch {
+	case h.Row < top:
+		top = h.Row
+	case h.Row >= top+vis:
+		top = h.Row - vis + 1
+	}
+	return max(0, min(top, len(h.Rows)-vis))
+}
+
+func (h Heatmap) View() string {
+	lw := 0
+	for _, r := range h.Rows {
+		lw = max(lw, ansi.StringWidth(r.Label))
+	}
+	lw = min(lw, max(8, h.Width/3))
+	// the newest columns are kept when not all fit
+	cols := min(len(h.Times), max(1, h.Width-lw-1))
+	off := len(h.Times) - cols
+
+	var b strings.Builder
+	end := min(len(h.Rows), h.Top+h.Visible())
+	for ri := h.Top; ri < end; ri++ {
+		r := h.Rows[ri]
+		label := ansi.Truncate(r.Label, lw, "…")
+		label += strings.Repeat(" ", lw-ansi.StringWidth(label))
+		switch {
+		case ri == h.Row:
+			label = h.Cursor.Render(label)
+		case r.Group:
+			label = h.Heading.Render(label)
+		}
+		b.WriteString(label + h.Axis.Render("│"))
+		for ci := 0; ci < cols; ci++ {
+			v := math.NaN()
+			if off+ci < len(r.Values) {
+				v = r.Values[off+ci]
+			}
+			cell := Shade(v)
+			switch {
+			case ri == h.Row && off+ci == h.Col:
+				cell = h.Cursor.Render(cell)
+			case !math.IsNaN(v) && h.Color != nil:
+				cell = h.Color(v).Render(cell)
+			}
+			b.WriteString(cell)
+		}
+		b.WriteByte('\n')
+	}
+	axis := []rune(strings.Repeat("─", cols))
+	if c := h.Col - off; c >= 0 && c < cols {
+		axis[c] = '┴'
+	}
+	b.WriteString(h.Axis.Render(strings.Repeat(" ", lw) + "└" + string(axis)))
+	if cols > 0 {
+		b.WriteString("\n" + h.Axis.Render(strings.Repeat(" ", lw+1)+timeAxis(h.Times[off:], cols, h.TimeFormat)))
+	}
+	return b.String()
+}
+
+// Buckets averages a series into n buckets of step ending at end; a bucket
+// holds the samples in (start, start+step]. Empty buckets are NaN.
+func Buckets(vals []float64, times []time.Time, end time.Time, step time.Duration, n int) []float64 {
+	sum, cnt := make([]float64, n), make([]int, n)
+	start := end.Add(-time.Duration(n) * step)
+	for i, t := range times {
+		if i >= len(vals) || !t.After(start) || t.After(end) {
+			continue
+		}
+		b := min(n-1, int((t.Sub(start)-1)/step))
+		sum[b] += vals[i]
+		cnt[b]++
+	}
+	for i := range sum {
+		if cnt[i] == 0 {
+			sum[i] = math.NaN()
+		} else {
+			sum[i] /= float64(cnt[i])
+		}
+	}
+	return sum
+}
@@ -0,0 +1,310 @@
+package widgets
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// LineChart plots series over time in braille, 2x4 dots per cell, with a
+// Y axis in the series' unit and an X axis from the sample times.
+type LineChart struct {
+	Width, Height int // cells, axes and legend included
+
+	Series []Series
+	Refs   []RefLine // horizontal lines: request, limit...
+
+	// Min and Max fix the Y range; with Max <= Min it runs from 0 to a
+	// round number above the data and the reference lines.
+	Min, Max float64
+	Binary   bool // round that number to a power of two, for bytes
+
+	YFormat    func(float64) string // Y labels; strconv 'g' when nil
+	TimeFormat string               // X labels; "15:04:05" when empty
+	Axis       lipgloss.Style
+}
+
+// Series is one line. Times, when set, is parallel to Values.
+type Series struct {
+	Name   string
+	Values []float64
+	Times  []time.Time
+	Style  lipgloss.Style
+}
+
+// RefLine is a dashed horizontal line at Value.
+type RefLine struct {
+	Name  string
+	Value float64
+	Style lipgloss.Style
+}
+
+// canvas is a grid of braille cells; owner is the index of the layer that
+// last drew into a cell, which picks the cell's color.
+type canvas struct {
+	w, h  int // cells
+	dots  []rune
+	owner []int
+}
+
+// brailleBits are the dot bits of a cell by [row][col].
+var brailleBits = [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+func newCanvas(w, h int) *canvas {
+	c := &canvas{w: w, h: h, dots: make([]rune, w*h), owner: make([]int, w*h)}
+	for i := range c.owner {
+		c.owner[i] = -1
+	}
+	return c
+}
+
+func (c *canvas) set(x, y, layer int) {
+	if x < 0 || y < 0 || x >= c.w*2 || y >= c.h*4 {
+		return
+	}
+	i := y/4*c.w + x/2
+	c.dots[i] |= brailleBits[y%4][x%2]
+	c.owner[i] = layer
+}
+
+// line draws from (x0,y0) to (x1,y1) in dots.
+func (c *canvas) line(x0, y0, x1, y1, layer int) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := sign(x1-x0), sign(y1-y0)
+	err := dx + dy
+	for {
+		c.set(x0, y0, layer)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		if e2 := 2 * err; e2 >= dy {
+			err += dy
+			x0 += sx
+		} else {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// MinMax downsamples vals to n buckets, keeping each bucket's first, last,
+// lowest and highest value so a one-sample spike still shows.
+func MinMax(vals []float64, n int) (first, last, lo, hi []float64) {
+	first, last = make([]float64, n), make([]float64, n)
+	lo, hi = make([]float64, n), make([]float64, n)
+	for b := 0; b < n; b++ {
+		i0, i1 := b*len(vals)/n, (b+1)*len(vals)/n
+		if i1 <= i0 {
+			i1 = i0 + 1
+		}
+		first[b], last[b], lo[b], hi[b] = vals[i0], vals[i1-1], vals[i0], vals[i0]
+		for _, v := range vals[i0:i1] {
+			lo[b], hi[b] = math.Min(lo[b], v), math.Max(hi[b], v)
+		}
+	}
+	return first, last, lo, hi
+}
+
+func (c LineChart) View() string {
+	yFmt := c.YFormat
+	if yFmt == nil {
+		yFmt = func(v float64) string { return strconv.FormatFloat(v, 'g', 3, 64) }
+	}
+	lo, hi := c.yRange()
+
+	legend := c.legend()
+	times := c.times()
+	rows := c.Height - 1 // x axis
+	if legend != "" {
+		rows--
+	}
+	if len(times) > 0 {
+		rows--
+	}
+	rows = max(1, rows)
+
+	// Y labels on the top, middle and bottom rows
+	labels := make([]string, rows)
+	labels[0], labels[rows-1] = yFmt(hi), yFmt(lo)
+	if rows >= 5 {
+		labels[rows/2] = yFmt(hi - (hi-lo)*float64(rows/2)/float64(rows-1))
+	}
+	lw := 0
+	for _, l := range labels {
+		lw = max(lw, ansi.StringWidth(l))
+	}
+	pw := max(1, c.Width-lw-1)
+
+	cv := newCanvas(pw, rows)
+	dotY := func(v float64) int {
+		f := (hi - v) / (hi - lo)
+		return int(math.Round(math.Max(0, math.Min(1, f)) * float64(rows*4-1)))
+	}
+	for i, r := range c.Refs {
+		y := dotY(r.Value)
+		for x := 0; x < pw*2; x++ {
+			if x%4 < 2 {
+				cv.set(x, y, -2-i)
+			}
+		}
+	}
+	for si, s := range c.Series {
+		c.plot(cv, s.Values, si, dotY)
+	}
+
+	var b strings.Builder
+	for r := 0; r < rows; r++ {
+		b.WriteString(c.Axis.Render(strings.Repeat(" ", lw-ansi.StringWidth(labels[r])) + labels[r] + "┤"))
+		for x := 0; x < pw; x++ {
+			i := r*pw + x
+			if cv.dots[i] == 0 {
+				b.WriteByte(' ')
+				continue
+			}
+			b.WriteString(c.layerStyle(cv.owner[i]).Render(string(0x2800 + cv.dots[i])))
+		}
+		b.WriteByte('\n')
+	}
+	b.WriteString(c.Axis.Render(strings.Repeat(" ", lw) + "└" + strings.Repeat("─", pw)))
+	if len(times) > 0 {
+		b.WriteString("\n" + c.Axis.Render(strings.Repeat(" ", lw+1)+timeAxis(times, pw, c.TimeFormat)))
+	}
+	if legend != "" {
+		b.WriteString("\n" + strings.Repeat(" ", lw+1) + legend)
+	}
+	return b.String()
+}
+
+// plot draws vals across the canvas: spread out when there are fewer
+// samples than dot columns, bucketed with MinMax when there are more.
+func (c LineChart) plot(cv *canvas, vals []float64, layer int, dotY func(float64) int) {
+	w := cv.w * 2
+	switch n := len(vals); {
+	case n == 0:
+	case n == 1:
+		cv.set(w-1, dotY(vals[0]), layer)
+	case n <= w:
+		px, py := 0, dotY(vals[0])
+		for i := 1; i < n; i++ {
+			x, y := i*(w-1)/(n-1), dotY(vals[i])
+			cv.line(px, py, x, y, layer)
+			px, py = x, y
+		}
+	default:
+		first, last, lo, hi := MinMax(vals, w)
+		for x := range first {
+			if x > 0 {
+				cv.line(x-1, dotY(last[x-1]), x, dotY(first[x]), layer)
+			}
+			cv.line(x, dotY(lo[x]), x, dotY(hi[x]), layer)
+		}
+	}
+}
+
+func (c LineChart) layerStyle(layer int) lipgloss.Style {
+	if layer >= 0 {
+		return c.Series[layer].Style
+	}
+	return c.Refs[-2-layer].Style
+}
+
+func (c LineChart) yRange() (lo, hi float64) {
+	if c.Max > c.Min {
+		return c.Min, c.Max
+	}
+	for _, s := range c.Series {
+		for _, v := range s.Values {
+			hi = math.Max(hi, v)
+		}
+	}
+	for _, r := range c.Refs {
+		hi = math.Max(hi, r.Value)
+	}
+	return 0, niceCeil(hi*1.05, c.Binary)
+}
+
+// niceCeil rounds v up to 1, 2 or 5 times a power of ten, or with binary
+// to a power of two.
+func niceCeil(v float64, binary bool) float64 {
+	if v <= 0 {
+		return 1
+	}
+	if binary {
+		return math.Pow(2, math.Ceil(math.Log2(v)))
+	}
+	p := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if m*p >= v {
+			return m * p
+		}
+	}
+	return 10 * p
+}
+
+// times are the sample times of the longest timed series.
+func (c LineChart) times() []time.Time {
+	var ts []time.Time
+	for _, s := range c.Series {
+		if len(s.Times) == len(s.Values) && len(s.Times) > len(ts) {
+			ts = s.Times
+		}
+	}
+	return ts
+}
+
+// timeAxis labels the first, middle and last sample time, as many as fit
+// in w cells; layout is "15:04:05" when empty.
+func timeAxis(ts []time.Time, w int, layout string) string {
+	if layout == "" {
+		layout = "15:04:05"
+	}
+	first, mid, last := ts[0].Format(layout), ts[len(ts)/2].Format(layout), ts[len(ts)-1].Format(layout)
+	n := len(first)
+	switch {
+	case w < n:
+		return ""
+	case w < 2*n+1:
+		return first
+	case w < 3*n+4:
+		return first + strings.Repeat(" ", w-2*n) + last
+	}
+	gap := w - 3*n
+	return first + strings.Repeat(" ", gap/2) + mid + strings.Repeat(" ", gap-gap/2) + last
+}
+
+// legend names the series and reference lines when there is more than one
+// thing to tell apart.
+func (c LineChart) legend() string {
+	if len(c.Series)+len(c.Refs) < 2 {
+		return ""
+	}
+	var parts []string
+	for _, s := range c.Series {
+		parts = append(parts, s.Style.Render("⣀⣀")+" "+s.Name)
+	}
+	for _, r := range c.Refs {
+		parts = append(parts, r.Style.Render("⠒ ⠒")+" "+r.Name)
+	}
+	return strings.Join(parts, "  ")
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
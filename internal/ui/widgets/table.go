@@ -0,0 +1,259 @@
+package widgets
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Table is a scrolling table like bubbles/table, but cells may carry ANSI
+// styling (match highlights, threshold colors): widths and truncation are
+// measured on what's visible, and the selected row's style survives resets
+// inside its cells.
+type Table struct {
+	cols   []Column
+	rows   []Row
+	tints  map[int]lipgloss.Style // whole-row styles by row index
+	cursor int
+	offset int // first row shown
+	height int // total, header included
+	width  int
+	focus  bool
+
+	KeyMap TableKeyMap
+	Styles TableStyles
+}
+
+type Column struct {
+	Title string
+	Width int
+}
+
+type Row []string
+
+type TableStyles struct {
+	Header   lipgloss.Style
+	Cell     lipgloss.Style
+	Selected lipgloss.Style
+}
+
+type TableKeyMap struct {
+	LineUp, LineDown         key.Binding
+	PageUp, PageDown         key.Binding
+	HalfPageUp, HalfPageDown key.Binding
+	GotoTop, GotoBottom      key.Binding
+}
+
+// DefaultTableKeyMap and DefaultTableStyles match bubbles/table.
+func DefaultTableKeyMap() TableKeyMap {
+	return TableKeyMap{
+		LineUp:       key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+		LineDown:     key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+		PageUp:       key.NewBinding(key.WithKeys("b", "pgup"), key.WithHelp("b/pgup", "page up")),
+		PageDown:     key.NewBinding(key.WithKeys("f", "pgdown", " "), key.WithHelp("f/pgdn", "page down")),
+		HalfPageUp:   key.NewBinding(key.WithKeys("u", "ctrl+u"), key.WithHelp("u", "½ page up")),
+		HalfPageDown: key.NewBinding(key.WithKeys("d", "ctrl+d"), key.WithHelp("d", "½ page down")),
+		GotoTop:      key.NewBinding(key.WithKeys("home", "g"), key.WithHelp("g/home", "go to start")),
+		GotoBottom:   key.NewBinding(key.WithKeys("end", "G"), key.WithHelp("G/end", "go to end")),
+	}
+}
+
+func DefaultTableStyles() TableStyles {
+	return TableStyles{
+		Header:   lipgloss.NewStyle().Bold(true).Padding(0, 1),
+		Cell:     lipgloss.NewStyle().Padding(0, 1),
+		Selected: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212")),
+	}
+}
+
+func NewTable() Table {
+	return Table{height: 20, KeyMap: DefaultTableKeyMap(), Styles: DefaultTableStyles()}
+}
+
+func (t *Table) SetColumns(c []Column) { t.cols = c }
+func (t *Table) SetRows(r []Row) {
+	t.rows = r
+	t.SetCursor(t.cursor)
+}
+func (t Table) Rows() []Row       { return t.rows }
+func (t Table) Columns() []Column { return t.cols }
+func (t *Table) SetWidth(w int)   { t.width = w }
+func (t Table) Width() int        { return t.width }
+
+// SetTints styles whole rows, by index into the rows; the cursor row keeps
+// the Selected style. Call it with the rows it belongs to.
+func (t *Table) SetTints(tints map[int]lipgloss.Style) { t.tints = tints }
+
+func (t *Table) SetHeight(h int) {
+	t.height = h
+	t.scroll()
+}
+func (t Table) Height() int { return t.height }
+func (t *Table) Focus()     { t.focus = true }
+func (t *Table) Blur()      { t.focus = false }
+func (t Table) Cursor() int { return t.cursor }
+
+func (t *Table) SetCursor(n int) {
+	t.cursor = clampInt(n, 0, len(t.rows)-1)
+	t.scroll()
+}
+
+// Offset is the index of the first visible row.
+func (t Table) Offset() int { return t.offset }
+
+// bodyHeight is the number of rows that fit under the header.
+func (t Table) bodyHeight() int {
+	return max(1, t.height-lipgloss.Height(t.headerView()))
+}
+
+// scroll keeps the cursor on screen.
+func (t *Table) scroll() {
+	h := t.bodyHeight()
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	}
+	if t.cursor >= t.offset+h {
+		t.offset = t.cursor - h + 1
+	}
+	t.offset = clampInt(t.offset, 0, max(0, len(t.rows)-h))
+}
+
+// RowAt is the row shown y lines below the table's top; false on the
+// header and below the last row.
+func (t Table) RowAt(y int) (int, bool) {
+	i := t.offset + y - lipgloss.Height(t.headerView())
+	if y < lipgloss.Height(t.headerView()) || y >= t.height || i >= len(t.rows) {
+		return 0, false
+	}
+	return i, true
+}
+
+// ColumnAt is the column under x, counted from the table's left edge.
+func (t Table) ColumnAt(x int) (int, bool) {
+	pad := t.Styles.Cell.GetHorizontalFrameSize()
+	for i, c := range t.cols {
+		if c.Width <= 0 {
+			continue
+		}
+		if x < c.Width+pad {
+			return i, x >= 0
+		}
+		x -= c.Width + pad
+	}
+	return 0, false
+}
+
+func (t *Table) MoveUp(n int)   { t.SetCursor(t.cursor - n) }
+func (t *Table) MoveDown(n int) { t.SetCursor(t.cursor + n) }
+
+func (t Table) Update(msg tea.Msg) (Table, tea.Cmd) {
+	if !t.focus {
+		return t, nil
+	}
+	k, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return t, nil
+	}
+	h := t.bodyHeight()
+	switch {
+	case key.Matches(k, t.KeyMap.LineUp):
+		t.MoveUp(1)
+	case key.Matches(k, t.KeyMap.LineDown):
+		t.MoveDown(1)
+	case key.Matches(k, t.KeyMap.PageUp):
+		t.MoveUp(h)
+	case key.Matches(k, t.KeyMap.PageDown):
+		t.MoveDown(h)
+	case key.Matches(k, t.KeyMap.HalfPageUp):
+		t.MoveUp(h / 2)
+	case key.Matches(k, t.KeyMap.HalfPageDown):
+		t.MoveDown(h / 2)
+	case key.Matches(k, t.KeyMap.GotoTop):
+		t.SetCursor(0)
+	case key.Matches(k, t.KeyMap.GotoBottom):
+		t.SetCursor(len(t.rows) - 1)
+	}
+	return t, nil
+}
+
+func (t Table) View() string {
+	lines := []string{t.headerView()}
+	h := t.bodyHeight()
+	for i := t.offset; i < len(t.rows) && i < t.offset+h; i++ {
+		lines = append(lines, t.rowView(i))
+	}
+	for len(lines) < h+1 {
+		lines = append(lines, "")
+	}
+	if t.width > 0 {
+		for i, l := range lines {
+			lines[i] = ansi.Truncate(l, t.width, "")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (t Table) headerView() string {
+	var b strings.Builder
+	for _, c := range t.cols {
+		if c.Width > 0 {
+			b.WriteString(t.Styles.Header.Render(fitCell(c.Title, c.Width)))
+		}
+	}
+	return b.String()
+}
+
+func (t Table) rowView(i int) string {
+	var b strings.Builder
+	for j, c := range t.cols {
+		if c.Width <= 0 {
+			continue
+		}
+		v := ""
+		if j < len(t.rows[i]) {
+			v = t.rows[i][j]
+		}
+		b.WriteString(t.Styles.Cell.Render(fitCell(v, c.Width)))
+	}
+	if i == t.cursor {
+		return Restyle(t.Styles.Selected, b.String())
+	}
+	if st, ok := t.tints[i]; ok {
+		return Restyle(st, b.String())
+	}
+	return b.String()
+}
+
+// fitCell truncates or pads s to exactly w visible cells.
+func fitCell(s string, w int) string {
+	if ansi.StringWidth(s) > w {
+		s = ansi.Truncate(s, w, "…")
+	}
+	return s + strings.Repeat(" ", max(0, w-ansi.StringWidth(s)))
+}
+
+// Restyle applies st to s, re-applying it after every reset inside s so
+// pre-styled fragments don't end the outer style early.
+func Restyle(st lipgloss.Style, s string) string {
+	marked := st.Render("\x00")
+	prefix, _, ok := strings.Cut(marked, "\x00")
+	if !ok || prefix == "" {
+		return st.Render(s)
+	}
+	s = strings.ReplaceAll(s, "\x1b[0m", "\x1b[0m"+prefix)
+	s = strings.ReplaceAll(s, "\x1b[m", "\x1b[m"+prefix)
+	return st.Render(s)
+}
+
+func clampInt(v, lo, hi int) int {
+	if v > hi {
+		v = hi
+	}
+	if v < lo {
+		v = lo
+	}
+	return v
+}
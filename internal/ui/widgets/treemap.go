@@ -0,0 +1,216 @@
+package widgets
+
+import (
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Treemap draws tiles as boxes with areas in proportion to their values,
+// laid out squarified (Bruls, Huizing and van Wijk) so the boxes stay close
+// to square; a cell counts as twice as tall as it is wide. Tiles must come
+// largest first. A tile's label goes in its top border, its Lines inside as
+// far as they fit.
+type Treemap struct {
+	Width, Height int
+
+	Tiles  []Tile
+	Sel    int            // selected tile, drawn with a heavy border
+	Cursor lipgloss.Style // the selected tile's border and label
+}
+
+type Tile struct {
+	Label string
+	Lines []string
+	Value float64 // tiles of 0 or less get no room
+	Color lipgloss.Style
+}
+
+// Rect is a tile's box in cells; empty for tiles that got no room.
+type Rect struct{ X, Y, W, H int }
+
+func (r Rect) empty() bool { return r.W <= 0 || r.H <= 0 }
+
+// Layout is the box of every tile.
+func (t Treemap) Layout() []Rect {
+	out := make([]Rect, len(t.Tiles))
+	n, total := 0, 0.0
+	for n < len(t.Tiles) && t.Tiles[n].Value > 0 {
+		total += t.Tiles[n].Value
+		n++
+	}
+	if n == 0 || t.Width < 1 || t.Height < 1 {
+		return out
+	}
+	// in square units: a cell is 1 wide and 2 tall
+	x, y, w, h := 0.0, 0.0, float64(t.Width), float64(2*t.Height)
+	areas := make([]float64, n)
+	for i := range areas {
+		areas[i] = t.Tiles[i].Value / total * w * h
+	}
+	cell := func(x0, y0, x1, y1 float64) Rect {
+		cx0, cy0 := int(math.Round(x0)), int(math.Round(y0/2))
+		return Rect{cx0, cy0, int(math.Round(x1)) - cx0, int(math.Round(y1/2)) - cy0}
+	}
+	for i := 0; i < n; {
+		short := math.Min(w, h)
+		j := i + 1
+		for j < n && worst(areas[i:j+1], short) <= worst(areas[i:j], short) {
+			j++
+		}
+		sum := 0.0
+		for _, a := range areas[i:j] {
+			sum += a
+		}
+		if w >= h { // a column along the left
+			cw, yy := sum/h, y
+			for k := i; k < j; k++ {
+				kh := areas[k] / cw
+				out[k] = cell(x, yy, x+cw, yy+kh)
+				yy += kh
+			}
+			x, w = x+cw, w-cw
+		} else { // a row along the top
+			rh, xx := sum/w, x
+			for k := i; k < j; k++ {
+				kw := areas[k] / rh
+				out[k] = cell(xx, y, xx+kw, y+rh)
+				xx += kw
+			}
+			y, h = y+rh, h-rh
+		}
+		i = j
+	}
+	return out
+}
+
+// worst is the highest aspect ratio of a row of areas laid along side.
+func worst(row []float64, side float64) float64 {
+	sum, hi, lo := 0.0, 0.0, math.Inf(1)
+	for _, a := range row {
+		sum += a
+		hi, lo = math.Max(hi, a), math.Min(lo, a)
+	}
+	s2, sum2 := side*side, sum*sum
+	return math.Max(s2*hi/sum2, sum2/(s2*lo))
+}
+
+// Next is the tile to move to from Sel in direction (dx, dy): the nearest
+// whose center lies that way, preferring ones straight ahead. Sel when there
+// is none.
+func (t Treemap) Next(rects []Rect, dx, dy int) int {
+	if t.Sel < 0 || t.Sel >= len(rects) {
+		return t.Sel
+	}
+	center := func(r Rect) (float64, float64) {
+		return float64(r.X) + float64(r.W)/2, 2 * (float64(r.Y) + float64(r.H)/2)
+	}
+	sx, sy := center(rects[t.Sel])
+	best, score := t.Sel, math.Inf(1)
+	for i, r := range rects {
+		if i == t.Sel || r.empty() {
+			continue
+		}
+		cx, cy := center(r)
+		ahead := (cx-sx)*float64(dx) + (cy-sy)*float64(dy)
+		side := math.Abs((cx-sx)*float64(dy)) + math.Abs((cy-sy)*float64(dx))
+		if ahead <= 0 {
+			continue
+		}
+		if s := ahead + 2*side; s < score {
+			best, score = i, s
+		}
+	}
+	return best
+}
+
+var (
+	thinBox  = [6]rune{'┌', '┐', '└', '┘', '─', '│'}
+	heavyBox = [6]rune{'┏', '┓', '┗', '┛', '━', '┃'}
+)
+
+func (t Treemap) View() string {
+	grid := make([][]rune, t.Height)
+	paint := make([][]int, t.Height) // tile+1 whose color a cell takes, 0 for none
+	for y := range grid {
+		grid[y] = []rune(strings.Repeat(" ", t.Width))
+		paint[y] = make([]int, t.Width)
+	}
+	set := func(x, y int, r rune, tile int) {
+		if y >= 0 && y < t.Height && x >= 0 && x < t.Width {
+			grid[y][x], paint[y][x] = r, tile+1
+		}
+	}
+	text := func(x, y, w int, s string, tile int) {
+		rs := []rune(s)
+		if len(rs) > w {
+			rs = append(rs[:max(0, w-1)], '…')
+		}
+		for i, r := range rs[:min(len(rs), w)] {
+			set(x+i, y, r, tile)
+		}
+	}
+	for i, r := range t.Layout() {
+		if r.empty() {
+			continue
+		}
+		if r.W < 2 || r.H < 2 { // no room for a box
+			for y := r.Y; y < r.Y+r.H; y++ {
+				for x := r.X; x < r.X+r.W; x++ {
+					set(x, y, '▒', i)
+				}
+			}
+			continue
+		}
+		b := thinBox
+		if i == t.Sel {
+			b = heavyBox
+		}
+		x1, y1 := r.X+r.W-1, r.Y+r.H-1
+		for x := r.X + 1; x < x1; x++ {
+			set(x, r.Y, b[4], i)
+			set(x, y1, b[4], i)
+		}
+		for y := r.Y + 1; y < y1; y++ {
+			set(r.X, y, b[5], i)
+			set(x1, y, b[5], i)
+		}
+		set(r.X, r.Y, b[0], i)
+		set(x1, r.Y, b[1], i)
+		set(r.X, y1, b[2], i)
+		set(x1, y1, b[3], i)
+		text(r.X+1, r.Y, r.W-2, t.Tiles[i].Label, i)
+		for k, l := range t.Tiles[i].Lines {
+			if r.Y+1+k >= y1 {
+				break
+			}
+			text(r.X+1, r.Y+1+k, r.W-2, l, i)
+		}
+	}
+
+	style := func(p int) lipgloss.Style {
+		if p-1 == t.Sel {
+			return t.Cursor
+		}
+		return t.Tiles[p-1].Color
+	}
+	lines := make([]string, t.Height)
+	for y, row := range grid {
+		var b strings.Builder
+		for x := 0; x < len(row); {
+			end := x
+			for end < len(row) && paint[y][end] == paint[y][x] {
+				end++
+			}
+			run := string(row[x:end])
+			if p := paint[y][x]; p > 0 {
+				run = style(p).Render(run)
+			}
+			b.WriteString(run)
+			x = end
+		}
+		lines[y] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}